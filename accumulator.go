@@ -0,0 +1,132 @@
+package groq
+
+import (
+	"errors"
+	"io"
+
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+// ChatCompletionAccumulator accumulates the chunks of a chat completion
+// stream into the response of the equivalent non-streaming request.
+//
+// The zero value is ready to use.
+type ChatCompletionAccumulator struct {
+	response ChatCompletionResponse
+}
+
+// Add adds a chunk of the stream to the accumulated response.
+//
+// Content and tool call argument deltas are concatenated per choice, tool
+// call fragments are stitched together by their index, and the usage is
+// taken from whichever chunk carries it.
+func (a *ChatCompletionAccumulator) Add(chunk *ChatCompletionStreamResponse) {
+	r := &a.response
+	if r.ID == "" {
+		r.ID = chunk.ID
+		r.Object = "chat.completion"
+		r.Created = chunk.Created
+		r.Model = chunk.Model
+	}
+	if chunk.SystemFingerprint != "" {
+		r.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.Usage != nil {
+		r.Usage = *chunk.Usage
+	}
+	if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+		r.Usage = *chunk.XGroq.Usage
+	}
+	for _, c := range chunk.Choices {
+		for len(r.Choices) <= c.Index {
+			r.Choices = append(r.Choices, ChatCompletionChoice{
+				Index:   len(r.Choices),
+				Message: ChatCompletionMessage{Role: RoleAssistant},
+			})
+		}
+		choice := &r.Choices[c.Index]
+		if c.Delta.Role != "" {
+			choice.Message.Role = Role(c.Delta.Role)
+		}
+		choice.Message.Content += c.Delta.Content
+		if c.Delta.FunctionCall != nil {
+			if choice.Message.FunctionCall == nil {
+				choice.Message.FunctionCall = &tools.FunctionCall{}
+			}
+			mergeFunctionCall(
+				choice.Message.FunctionCall,
+				*c.Delta.FunctionCall,
+			)
+		}
+		for i, call := range c.Delta.ToolCalls {
+			index := i
+			if call.Index != nil {
+				index = *call.Index
+			}
+			for len(choice.Message.ToolCalls) <= index {
+				choice.Message.ToolCalls = append(
+					choice.Message.ToolCalls,
+					tools.ToolCall{},
+				)
+			}
+			merged := &choice.Message.ToolCalls[index]
+			if call.ID != "" {
+				merged.ID = call.ID
+			}
+			if call.Type != "" {
+				merged.Type = call.Type
+			}
+			mergeFunctionCall(&merged.Function, call.Function)
+		}
+		if c.FinishReason != "" {
+			choice.FinishReason = c.FinishReason
+		}
+	}
+}
+
+// Response returns the accumulated response.
+func (a *ChatCompletionAccumulator) Response() ChatCompletionResponse {
+	response := a.response
+	response.Choices = append(
+		[]ChatCompletionChoice(nil),
+		a.response.Choices...,
+	)
+	for i := range response.Choices {
+		message := &response.Choices[i].Message
+		message.ToolCalls = append([]tools.ToolCall(nil), message.ToolCalls...)
+		if message.FunctionCall != nil {
+			call := *message.FunctionCall
+			message.FunctionCall = &call
+		}
+	}
+	return response
+}
+
+// Accumulate receives the remaining chunks of the stream and returns the
+// response of the equivalent non-streaming request.
+//
+// It does not close the stream.
+func (s *ChatCompletionStream) Accumulate() (ChatCompletionResponse, error) {
+	var a ChatCompletionAccumulator
+	for {
+		chunk, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return a.Response(), err
+		}
+		a.Add(chunk)
+	}
+	response := a.Response()
+	response.SetHeader(s.Header)
+	return response, nil
+}
+
+// mergeFunctionCall merges a function call fragment into a function call.
+func mergeFunctionCall(call *tools.FunctionCall, fragment tools.FunctionCall) {
+	if call.Name == "" {
+		call.Name = fragment.Name
+	}
+	call.Arguments += fragment.Arguments
+}
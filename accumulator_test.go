@@ -0,0 +1,127 @@
+package groq
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+// toolCallChunks are the chunks of a stream calling the get_weather tool,
+// with the arguments split across chunks, next to a second text choice.
+var toolCallChunks = []string{
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama-3.3-70b-versatile","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"index":0,"id":"call_1","type":"function","function":{"name":"get_weather","arguments":""}}]}},{"index":1,"delta":{"role":"assistant","content":"It is"}}]}`,
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama-3.3-70b-versatile","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]}},{"index":1,"delta":{"content":" sunny."},"finish_reason":"stop"}]}`,
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama-3.3-70b-versatile","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}},{"index":1,"id":"call_2","type":"function","function":{"name":"get_time","arguments":"{}"}}]},"finish_reason":"tool_calls"}]}`,
+	`{"id":"chatcmpl-1","object":"chat.completion.chunk","created":1,"model":"llama-3.3-70b-versatile","choices":[],"x_groq":{"id":"req_1","usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}}`,
+}
+
+// handleStreamEndpoint answers with a server sent event for each of the
+// given chunks.
+func handleStreamEndpoint(
+	t *testing.T,
+	chunks ...string,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, err := io.WriteString(w, streamBody(chunks...))
+		assert.NoError(t, err)
+	}
+}
+
+func TestChatCompletionAccumulator(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		handleStreamEndpoint(t, toolCallChunks...),
+	)
+	stream, err := client.ChatCompletionStream(
+		context.Background(),
+		ChatCompletionRequest{
+			Model: ModelLlama3370BVersatile,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "What is the weather in Paris?"},
+			},
+			Stream: true,
+		},
+	)
+	a.NoError(err)
+	defer stream.Close()
+	response, err := stream.Accumulate()
+	a.NoError(err)
+	a.Equal("chatcmpl-1", response.ID)
+	a.Equal("chat.completion", response.Object)
+	a.Equal(ModelLlama3370BVersatile, response.Model)
+	a.Equal(Usage{
+		PromptTokens:     10,
+		CompletionTokens: 20,
+		TotalTokens:      30,
+	}, response.Usage)
+	a.Equal([]ChatCompletionChoice{
+		{
+			Index: 0,
+			Message: ChatCompletionMessage{
+				Role: RoleAssistant,
+				ToolCalls: []tools.ToolCall{
+					{
+						ID:   "call_1",
+						Type: "function",
+						Function: tools.FunctionCall{
+							Name:      "get_weather",
+							Arguments: `{"city":"Paris"}`,
+						},
+					},
+					{
+						ID:   "call_2",
+						Type: "function",
+						Function: tools.FunctionCall{
+							Name:      "get_time",
+							Arguments: "{}",
+						},
+					},
+				},
+			},
+			FinishReason: ReasonToolCalls,
+		},
+		{
+			Index: 1,
+			Message: ChatCompletionMessage{
+				Role:    RoleAssistant,
+				Content: "It is sunny.",
+			},
+			FinishReason: ReasonStop,
+		},
+	}, response.Choices)
+}
+
+func TestChatCompletionAccumulatorResponse(t *testing.T) {
+	a := assert.New(t)
+	var acc ChatCompletionAccumulator
+	acc.Add(&ChatCompletionStreamResponse{
+		ID: "chatcmpl-1",
+		Choices: []ChatCompletionStreamChoice{
+			{Delta: ChatCompletionStreamChoiceDelta{Content: "Hello"}},
+		},
+	})
+	first := acc.Response()
+	acc.Add(&ChatCompletionStreamResponse{
+		ID: "chatcmpl-1",
+		Choices: []ChatCompletionStreamChoice{
+			{
+				Delta:        ChatCompletionStreamChoiceDelta{Content: "!"},
+				FinishReason: ReasonStop,
+			},
+		},
+		Usage: &Usage{TotalTokens: 3},
+	})
+	a.Equal("Hello", first.Choices[0].Message.Content)
+	second := acc.Response()
+	a.Equal("Hello!", second.Choices[0].Message.Content)
+	a.Equal(ReasonStop, second.Choices[0].FinishReason)
+	a.Equal(3, second.Usage.TotalTokens)
+}
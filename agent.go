@@ -0,0 +1,181 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+const defaultAgentMaxIterations = 10
+
+type (
+	// ToolFunc executes a single tool call and returns the content of the
+	// tool message answering it.
+	ToolFunc func(ctx context.Context, call tools.ToolCall) (string, error)
+	// AgentTool is a tool definition along with the function executing
+	// its calls.
+	AgentTool struct {
+		// Tool is the definition of the tool sent to the model.
+		Tool tools.Tool
+		// Func executes the calls of the tool.
+		Func ToolFunc
+	}
+	// Agent drives a conversation with a model, executing the tool calls
+	// the model requests until it stops calling tools.
+	Agent struct {
+		client        *Client
+		tools         []AgentTool
+		maxIterations int
+		tokenBudget   int
+		// err is the error of an invalid option, returned by Run.
+		err error
+	}
+	// AgentOption is a function that sets options for an Agent.
+	AgentOption func(*Agent)
+	// AgentResult is the outcome of an agent run.
+	AgentResult struct {
+		// Messages is the full transcript of the run, starting with the
+		// messages of the request.
+		Messages []ChatCompletionMessage
+		// Response is the last chat completion response of the run.
+		Response ChatCompletionResponse
+		// Usage is the token usage summed over the run.
+		Usage Usage
+		// Iterations is the number of chat completions made.
+		Iterations int
+	}
+)
+
+// WithAgentTools registers tools, and the functions executing them, on the
+// agent.
+func WithAgentTools(tools ...AgentTool) AgentOption {
+	return func(a *Agent) { a.tools = append(a.tools, tools...) }
+}
+
+// WithMaxIterations sets the maximum number of chat completions an agent
+// makes in a run. It defaults to 10.
+//
+// It must be positive, runs of agents created otherwise fail.
+func WithMaxIterations(n int) AgentOption {
+	return func(a *Agent) {
+		if n <= 0 {
+			a.err = fmt.Errorf(
+				"agent max iterations must be positive, got %d",
+				n,
+			)
+			return
+		}
+		a.maxIterations = n
+	}
+}
+
+// WithTokenBudget sets the maximum number of tokens an agent uses in a run.
+//
+// The budget is checked after each chat completion, so a run can exceed it
+// by the usage of its last completion.
+func WithTokenBudget(tokens int) AgentOption {
+	return func(a *Agent) { a.tokenBudget = tokens }
+}
+
+// NewAgent creates a new agent using the given client.
+func NewAgent(client *Client, opts ...AgentOption) *Agent {
+	a := &Agent{
+		client:        client,
+		maxIterations: defaultAgentMaxIterations,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Run sends the request and executes the tool calls of the model's
+// responses, sending their results back, until the model stops or answers
+// without calling tools.
+//
+// A response truncated by its token limit fails the run with a
+// groqerr.ErrTruncated. The tools registered on the agent are added to the
+// tools of the request. The result holds the transcript up to the point of
+// failure when an error is returned.
+func (a *Agent) Run(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (result AgentResult, err error) {
+	if a.err != nil {
+		return result, a.err
+	}
+	fns := make(map[string]ToolFunc, len(a.tools))
+	request.Tools = append([]tools.Tool(nil), request.Tools...)
+	for _, t := range a.tools {
+		fns[t.Tool.Function.Name] = t.Func
+		request.Tools = append(request.Tools, t.Tool)
+	}
+	result.Messages = append(
+		[]ChatCompletionMessage(nil),
+		request.Messages...,
+	)
+	for result.Iterations < a.maxIterations {
+		request.Messages = result.Messages
+		result.Response, err = a.client.ChatCompletion(ctx, request)
+		if err != nil {
+			return result, err
+		}
+		result.Iterations++
+		result.Usage.PromptTokens += result.Response.Usage.PromptTokens
+		result.Usage.CompletionTokens += result.Response.Usage.CompletionTokens
+		result.Usage.TotalTokens += result.Response.Usage.TotalTokens
+		if len(result.Response.Choices) == 0 {
+			return result, fmt.Errorf(
+				"response %s has no choices",
+				result.Response.ID,
+			)
+		}
+		choice := result.Response.Choices[0]
+		result.Messages = append(result.Messages, choice.Message)
+		switch choice.FinishReason {
+		case ReasonLength:
+			return result, &groqerr.ErrTruncated{
+				ResponseID: result.Response.ID,
+			}
+		case ReasonStop:
+			return result, nil
+		}
+		if len(choice.Message.ToolCalls) == 0 {
+			return result, nil
+		}
+		for _, call := range choice.Message.ToolCalls {
+			fn, ok := fns[call.Function.Name]
+			if !ok {
+				return result, groqerr.ErrToolNotFound{
+					ToolName: call.Function.Name,
+				}
+			}
+			content, err := fn(ctx, call)
+			if err != nil {
+				return result, fmt.Errorf(
+					"running tool %s: %w",
+					call.Function.Name,
+					err,
+				)
+			}
+			result.Messages = append(result.Messages, ChatCompletionMessage{
+				Role:       RoleTool,
+				Name:       call.Function.Name,
+				Content:    content,
+				ToolCallID: call.ID,
+			})
+		}
+		if a.tokenBudget > 0 && result.Usage.TotalTokens >= a.tokenBudget {
+			return result, &groqerr.ErrAgentLimit{
+				Limit: "token budget",
+				Value: a.tokenBudget,
+			}
+		}
+	}
+	return result, &groqerr.ErrAgentLimit{
+		Limit: "max iterations",
+		Value: a.maxIterations,
+	}
+}
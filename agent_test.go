@@ -0,0 +1,171 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+// handleAgentEndpoint answers with a call to the weather tool until the
+// conversation holds a tool message, or always when loop is set.
+func handleAgentEndpoint(
+	t *testing.T,
+	loop bool,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		message := ChatCompletionMessage{
+			Role: RoleAssistant,
+			ToolCalls: []tools.ToolCall{{
+				ID:   "call_1",
+				Type: "function",
+				Function: tools.FunctionCall{
+					Name:      "get_weather",
+					Arguments: `{"city":"Paris"}`,
+				},
+			}},
+		}
+		reason := ReasonToolCalls
+		last := req.Messages[len(req.Messages)-1]
+		if last.Role == RoleTool && !loop {
+			message = ChatCompletionMessage{
+				Role:    RoleAssistant,
+				Content: "It is sunny in Paris.",
+			}
+			reason = ReasonStop
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(ChatCompletionResponse{
+			ID: "chatcmpl-123",
+			Choices: []ChatCompletionChoice{
+				{Message: message, FinishReason: reason},
+			},
+			Usage: Usage{TotalTokens: 10},
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestAgent(t *testing.T) {
+	ctx := context.Background()
+	weather := AgentTool{
+		Tool: tools.Tool{
+			Type:     tools.ToolTypeFunction,
+			Function: tools.FunctionDefinition{Name: "get_weather"},
+		},
+		Func: func(_ context.Context, call tools.ToolCall) (string, error) {
+			return "sunny in " + call.Function.Arguments, nil
+		},
+	}
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "What is the weather in Paris?"},
+		},
+	}
+	t.Run("stop", func(t *testing.T) {
+		a := assert.New(t)
+		client, server, teardown := setupGroqTestServer()
+		defer teardown()
+		server.RegisterHandler(
+			"/v1/chat/completions",
+			handleAgentEndpoint(t, false),
+		)
+		result, err := NewAgent(client, WithAgentTools(weather)).Run(ctx, req)
+		a.NoError(err)
+		a.Equal(2, result.Iterations)
+		a.Equal(20, result.Usage.TotalTokens)
+		a.Len(result.Messages, 4)
+		a.Equal(RoleTool, result.Messages[2].Role)
+		a.Equal("call_1", result.Messages[2].ToolCallID)
+		a.Equal(`sunny in {"city":"Paris"}`, result.Messages[2].Content)
+		a.Equal("It is sunny in Paris.", result.Messages[3].Content)
+		a.Len(req.Messages, 1)
+	})
+	t.Run("limits", func(t *testing.T) {
+		a := assert.New(t)
+		client, server, teardown := setupGroqTestServer()
+		defer teardown()
+		server.RegisterHandler(
+			"/v1/chat/completions",
+			handleAgentEndpoint(t, true),
+		)
+		var limit *groqerr.ErrAgentLimit
+		result, err := NewAgent(
+			client,
+			WithAgentTools(weather),
+			WithMaxIterations(3),
+		).Run(ctx, req)
+		a.True(errors.As(err, &limit))
+		a.Equal("max iterations", limit.Limit)
+		a.Equal(3, result.Iterations)
+		a.Len(result.Messages, 7)
+
+		result, err = NewAgent(
+			client,
+			WithAgentTools(weather),
+			WithTokenBudget(15),
+		).Run(ctx, req)
+		a.True(errors.As(err, &limit))
+		a.Equal("token budget", limit.Limit)
+		a.Equal(2, result.Iterations)
+
+		_, err = NewAgent(client).Run(ctx, req)
+		a.ErrorIs(err, groqerr.ErrToolNotFound{ToolName: "get_weather"})
+
+		_, err = NewAgent(client, WithMaxIterations(0)).Run(ctx, req)
+		a.ErrorContains(err, "max iterations must be positive")
+	})
+	t.Run("finish reasons", func(t *testing.T) {
+		a := assert.New(t)
+		client, server, teardown := setupGroqTestServer()
+		defer teardown()
+		var reason FinishReason
+		server.RegisterHandler(
+			"/v1/chat/completions",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				err := json.NewEncoder(w).Encode(ChatCompletionResponse{
+					ID: "chatcmpl-123",
+					Choices: []ChatCompletionChoice{{
+						Message: ChatCompletionMessage{
+							Role: RoleAssistant,
+							ToolCalls: []tools.ToolCall{{
+								ID:       "call_1",
+								Type:     "function",
+								Function: tools.FunctionCall{Name: "get_weather"},
+							}},
+						},
+						FinishReason: reason,
+					}},
+				})
+				assert.NoError(t, err)
+			},
+		)
+		agent := NewAgent(client, WithAgentTools(weather))
+		// tool calls of a stopped response are not executed
+		reason = ReasonStop
+		result, err := agent.Run(ctx, req)
+		a.NoError(err)
+		a.Equal(1, result.Iterations)
+		a.Len(result.Messages, 2)
+
+		reason = ReasonLength
+		result, err = agent.Run(ctx, req)
+		var truncated *groqerr.ErrTruncated
+		a.True(errors.As(err, &truncated))
+		a.Equal("chatcmpl-123", truncated.ResponseID)
+		a.Equal(1, result.Iterations)
+	})
+}
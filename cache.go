@@ -0,0 +1,393 @@
+package groq
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/conneroisu/groq-go/internal/list"
+)
+
+type (
+	// CacheStore stores the responses cached by a Groq client.
+	//
+	// Implementations must be safe for concurrent use.
+	CacheStore interface {
+		// Get returns the value stored for the key and whether it was
+		// found and is not expired.
+		Get(ctx context.Context, key string) ([]byte, bool, error)
+		// Set stores the value for the key, expiring it after the ttl
+		// unless the ttl is zero.
+		Set(
+			ctx context.Context,
+			key string,
+			value []byte,
+			ttl time.Duration,
+		) error
+	}
+	// MemoryCache is an in-memory CacheStore evicting its least recently
+	// used entries once full.
+	MemoryCache struct {
+		mu      sync.Mutex
+		size    int
+		entries *list.List[*cacheEntry]
+		index   map[string]*list.Element[*cacheEntry]
+		now     func() time.Time
+	}
+	// DiskCache is a CacheStore keeping each of its entries in a file of a
+	// directory, so that they survive the process, e.g. across CI runs.
+	DiskCache struct {
+		dir string
+		now func() time.Time
+	}
+	// cacheEntry is an entry of a cache store.
+	cacheEntry struct {
+		Key     string    `json:"key"`
+		Value   []byte    `json:"value"`
+		Expires time.Time `json:"expires"`
+	}
+	// bypassCacheKey is the context key of calls bypassing the cache.
+	bypassCacheKey struct{}
+)
+
+// NewMemoryCache returns an in-memory cache holding at most size entries.
+//
+// A size of zero or less leaves the cache unbounded.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		entries: list.New[*cacheEntry](),
+		index:   map[string]*list.Element[*cacheEntry]{},
+		now:     time.Now,
+	}
+}
+
+// Get implements the CacheStore interface.
+func (m *MemoryCache) Get(
+	_ context.Context,
+	key string,
+) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.index[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if e.Value.expired(m.now()) {
+		m.entries.Remove(e)
+		delete(m.index, key)
+		return nil, false, nil
+	}
+	m.entries.MoveToFront(e)
+	return e.Value.Value, true, nil
+}
+
+// Set implements the CacheStore interface.
+func (m *MemoryCache) Set(
+	_ context.Context,
+	key string,
+	value []byte,
+	ttl time.Duration,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry := newCacheEntry(key, value, ttl, m.now())
+	if e, ok := m.index[key]; ok {
+		e.Value = entry
+		m.entries.MoveToFront(e)
+		return nil
+	}
+	m.index[key] = m.entries.PushFront(entry)
+	for m.size > 0 && m.entries.Len() > m.size {
+		oldest := m.entries.Remove(m.entries.Back())
+		delete(m.index, oldest.Key)
+	}
+	return nil
+}
+
+// Len returns the number of entries of the cache, including expired ones
+// not yet evicted.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.entries.Len()
+}
+
+// NewDiskCache returns a cache storing its entries in the directory,
+// creating it if needed.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir, now: time.Now}, nil
+}
+
+// Get implements the CacheStore interface.
+//
+// Expired entries are removed from the directory as they are found.
+func (d *DiskCache) Get(
+	_ context.Context,
+	key string,
+) ([]byte, bool, error) {
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var entry cacheEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil || entry.Key != key || entry.expired(d.now()) {
+		_ = os.Remove(path)
+		return nil, false, nil
+	}
+	return entry.Value, true, nil
+}
+
+// Set implements the CacheStore interface.
+//
+// Entries are written to a temporary file first so that concurrent readers
+// never see a partially written entry.
+func (d *DiskCache) Set(
+	_ context.Context,
+	key string,
+	value []byte,
+	ttl time.Duration,
+) error {
+	data, err := json.Marshal(newCacheEntry(key, value, ttl, d.now()))
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// path returns the path of the file of the entry of the key.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// newCacheEntry returns an entry expiring after the ttl from now.
+func newCacheEntry(
+	key string,
+	value []byte,
+	ttl time.Duration,
+	now time.Time,
+) *cacheEntry {
+	entry := &cacheEntry{Key: key, Value: value}
+	if ttl > 0 {
+		entry.Expires = now.Add(ttl)
+	}
+	return entry
+}
+
+// expired reports whether the entry is expired at the given time.
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// WithCache caches the chat completion, structured output, transcription
+// and translation responses of the Groq client in the store, see Cache.
+func WithCache(store CacheStore, ttl time.Duration) Opts {
+	return WithMiddleware(Cache(store, ttl))
+}
+
+// Cache returns a middleware answering the calls it has already seen with
+// the responses stored in the store, which expire after the ttl unless it
+// is zero.
+//
+// Only reproducible calls are cached: chat completions, including
+// structured outputs, with a fixed seed, whatever their temperature, and
+// transcriptions and translations left at the default temperature whose
+// audio is a file or an io.ReadSeeker. A zero chat temperature is not sent
+// to the API, so it does not make a request without a seed cacheable.
+//
+// Calls are keyed by a hash of their request and audio, which is streamed
+// into the hash rather than buffered. Structured replies not valid against
+// the JSON schema of their request are not stored. Cached responses carry
+// no headers.
+//
+// Errors of the store are treated as cache misses so that a failing store
+// never fails a call.
+func Cache(store CacheStore, ttl time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, error) {
+			var decode func([]byte) (any, error)
+			switch call.Operation {
+			case OperationChatCompletion:
+				decode = decodeCached[ChatCompletionResponse]
+			case OperationTranscription, OperationTranslation:
+				decode = decodeCached[AudioResponse]
+			}
+			if decode == nil || !cacheable(call) ||
+				ctx.Value(bypassCacheKey{}) != nil {
+				return next(ctx, call)
+			}
+			key, err := cacheKey(call)
+			if err != nil {
+				return nil, err
+			}
+			data, ok, err := store.Get(ctx, key)
+			if err == nil && ok {
+				res, err := decode(data)
+				if err == nil {
+					return res, nil
+				}
+			}
+			res, err := next(ctx, call)
+			if err != nil || !validResponse(call, res) {
+				return res, err
+			}
+			data, err = json.Marshal(res)
+			if err == nil {
+				_ = store.Set(ctx, key, data, ttl)
+			}
+			return res, nil
+		}
+	}
+}
+
+// BypassCache returns a context whose calls neither read from nor write to
+// the cache of the client.
+func BypassCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+// cacheable reports whether the call is reproducible, and so can be
+// cached.
+func cacheable(call *Call) bool {
+	switch req := call.Request.(type) {
+	case *ChatCompletionRequest:
+		return req.Seed != nil
+	case *AudioRequest:
+		if req.Reader != nil {
+			_, ok := req.Reader.(io.ReadSeeker)
+			if !ok {
+				return false
+			}
+		}
+		return req.Temperature == 0
+	}
+	return false
+}
+
+// validResponse reports whether each choice of a chat completion response
+// is valid against the JSON schema of its request, if any.
+func validResponse(call *Call, res any) bool {
+	req, ok := call.Request.(*ChatCompletionRequest)
+	if !ok || req.ResponseFormat == nil ||
+		req.ResponseFormat.JSONSchema == nil {
+		return true
+	}
+	response, ok := res.(ChatCompletionResponse)
+	if !ok {
+		return false
+	}
+	s := &req.ResponseFormat.JSONSchema.Schema
+	for i := range response.Choices {
+		var out any
+		err := parseChoice(response, i, s, &out)
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeCached decodes a cached response.
+func decodeCached[Resp any](data []byte) (any, error) {
+	var res Resp
+	err := json.Unmarshal(data, &res)
+	return res, err
+}
+
+// cacheKey returns the key of the response of a call: a hash of its
+// operation and canonical request.
+func cacheKey(call *Call) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "groq-go/%s\n", call.Operation)
+	enc := json.NewEncoder(h)
+	switch req := call.Request.(type) {
+	case *ChatCompletionRequest:
+		err := enc.Encode(req)
+		if err != nil {
+			return "", err
+		}
+	case *AudioRequest:
+		err := enc.Encode(struct {
+			Model       AudioModel
+			File        string
+			Prompt      string
+			Temperature float32
+			Language    string
+			Format      Format
+		}{
+			req.Model,
+			filepath.Base(req.FilePath),
+			req.Prompt,
+			req.Temperature,
+			req.Language,
+			req.Format,
+		})
+		if err != nil {
+			return "", err
+		}
+		err = hashAudio(h, req)
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashAudio writes the audio of the request to the hash.
+//
+// The reader of the request, which must be an io.ReadSeeker, is rewound
+// to where it started so that the request can still be sent.
+func hashAudio(h io.Writer, req *AudioRequest) error {
+	if r, ok := req.Reader.(io.ReadSeeker); ok {
+		start, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("reading audio: %w", err)
+		}
+		_, err = io.Copy(h, r)
+		if err != nil {
+			return fmt.Errorf("reading audio: %w", err)
+		}
+		_, err = r.Seek(start, io.SeekStart)
+		return err
+	}
+	f, err := os.Open(req.FilePath)
+	if err != nil {
+		return fmt.Errorf("opening audio file: %w", err)
+	}
+	defer f.Close()
+	_, err = io.Copy(h, f)
+	return err
+}
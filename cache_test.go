@@ -0,0 +1,194 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	var chats, transcriptions atomic.Int32
+	echo := handleEchoEndpoint(t)
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			chats.Add(1)
+			echo(w, r)
+		},
+	)
+	server.RegisterHandler(
+		"/v1/audio/transcriptions",
+		func(w http.ResponseWriter, r *http.Request) {
+			transcriptions.Add(1)
+			audio, _, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(audio)
+			_, _ = w.Write([]byte(`{"text": "` + string(data) + `"}`))
+		},
+	)
+	WithCache(NewMemoryCache(8), time.Hour)(client)
+	seed := 42
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Seed:  &seed,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: `{"name": "groq"}`},
+		},
+	}
+	for range 2 {
+		response, err := client.ChatCompletion(ctx, req)
+		a.NoError(err)
+		a.Equal(`{"name": "groq"}`, response.Choices[0].Message.Content)
+	}
+	a.Equal(int32(1), chats.Load())
+
+	other := req
+	other.Messages = []ChatCompletionMessage{
+		{Role: RoleUser, Content: `{"name": "cache"}`},
+	}
+	_, err := client.ChatCompletion(ctx, other)
+	a.NoError(err)
+	a.Equal(int32(2), chats.Load())
+
+	_, err = client.ChatCompletion(BypassCache(ctx), req)
+	a.NoError(err)
+	a.Equal(int32(3), chats.Load())
+
+	var out struct {
+		Name string `json:"name"`
+	}
+	for range 2 {
+		err = client.ChatCompletionJSON(ctx, req, &out)
+		a.NoError(err)
+		a.Equal("groq", out.Name)
+	}
+	a.Equal(int32(4), chats.Load())
+
+	// structured replies failing validation are not stored
+	var strict struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	for range 2 {
+		err = client.ChatCompletionJSON(ctx, req, &strict)
+		var outErr *groqerr.ErrStructuredOutput
+		a.ErrorAs(err, &outErr)
+	}
+	a.Equal(int32(6), chats.Load())
+
+	// requests without a seed always reach the server, even at a zero
+	// temperature which is not sent
+	random := req
+	random.Seed = nil
+	for range 2 {
+		_, err = client.ChatCompletion(ctx, random)
+		a.NoError(err)
+	}
+	a.Equal(int32(8), chats.Load())
+	// only the seed is checked, whatever the temperature
+	seeded := req
+	seeded.Temperature = 0.7
+	for range 2 {
+		_, err = client.ChatCompletion(ctx, seeded)
+		a.NoError(err)
+	}
+	a.Equal(int32(9), chats.Load())
+
+	for range 2 {
+		audio, err := client.Transcribe(ctx, AudioRequest{
+			Model:    ModelWhisperLargeV3,
+			FilePath: "hello.mp3",
+			Reader:   bytes.NewReader([]byte("hello")),
+		})
+		a.NoError(err)
+		a.Equal("hello", audio.Text)
+	}
+	a.Equal(int32(1), transcriptions.Load())
+
+	// audio which cannot be rewound is not hashed
+	for range 2 {
+		audio, err := client.Transcribe(ctx, AudioRequest{
+			Model:    ModelWhisperLargeV3,
+			FilePath: "hello.mp3",
+			Reader:   io.MultiReader(bytes.NewReader([]byte("hello"))),
+		})
+		a.NoError(err)
+		a.Equal("hello", audio.Text)
+	}
+	a.Equal(int32(3), transcriptions.Load())
+}
+
+func TestMemoryCache(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	cache := NewMemoryCache(2)
+	cache.now = func() time.Time { return now }
+	a.NoError(cache.Set(ctx, "a", []byte("1"), 0))
+	a.NoError(cache.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ := cache.Get(ctx, "a")
+	a.True(ok)
+
+	// b is the least recently used entry
+	a.NoError(cache.Set(ctx, "c", []byte("3"), 0))
+	a.Equal(2, cache.Len())
+	_, ok, _ = cache.Get(ctx, "b")
+	a.False(ok)
+
+	a.NoError(cache.Set(ctx, "c", []byte("4"), time.Minute))
+	value, ok, _ := cache.Get(ctx, "c")
+	a.True(ok)
+	a.Equal([]byte("4"), value)
+	now = now.Add(time.Minute)
+	_, ok, _ = cache.Get(ctx, "c")
+	a.False(ok)
+	_, ok, _ = cache.Get(ctx, "a")
+	a.True(ok)
+	a.Equal(1, cache.Len())
+}
+
+func TestDiskCache(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	now := time.Unix(0, 0)
+	cache, err := NewDiskCache(dir)
+	a.NoError(err)
+	cache.now = func() time.Time { return now }
+	a.NoError(cache.Set(ctx, "a", []byte("1"), 0))
+	a.NoError(cache.Set(ctx, "b", []byte("2"), time.Minute))
+
+	reopened, err := NewDiskCache(dir)
+	a.NoError(err)
+	reopened.now = cache.now
+	value, ok, err := reopened.Get(ctx, "a")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal([]byte("1"), value)
+	_, ok, err = reopened.Get(ctx, "missing")
+	a.NoError(err)
+	a.False(ok)
+
+	now = now.Add(time.Minute)
+	_, ok, err = reopened.Get(ctx, "b")
+	a.NoError(err)
+	a.False(ok)
+	entries, err := os.ReadDir(dir)
+	a.NoError(err)
+	a.Len(entries, 1, "expired entries are removed")
+}
@@ -0,0 +1,76 @@
+package groq
+
+import (
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+// ModelCapabilities describes the limits and features of a model.
+//
+// The capabilities of known models are generated alongside models.go.
+type ModelCapabilities struct {
+	// ContextWindow is the maximum number of tokens of the context of the
+	// model.
+	ContextWindow int
+	// MaxCompletionTokens is the maximum number of tokens the model can
+	// generate.
+	MaxCompletionTokens int
+	// Vision is whether the model accepts image inputs.
+	Vision bool
+	// Tools is whether the model supports tool use.
+	Tools bool
+	// JSONMode is whether the model supports json response formats.
+	JSONMode bool
+	// Deprecated is whether the model is deprecated and scheduled for
+	// removal from the api.
+	Deprecated bool
+	// Replacement is the model recommended in place of a deprecated model.
+	Replacement Model
+}
+
+// ModelInfo returns the capabilities of the given model and whether the model
+// is known to the registry.
+func ModelInfo[
+	T ChatModel | AudioModel | ModerationModel | EmbeddingModel,
+](model T) (ModelCapabilities, bool) {
+	info, ok := modelRegistry[Model(model)]
+	return info, ok
+}
+
+// validateChatRequest checks that the model of the request supports the
+// features used by the request.
+//
+// Requests to models unknown to the registry are not checked.
+func validateChatRequest(request ChatCompletionRequest) error {
+	info, ok := ModelInfo(request.Model)
+	if !ok {
+		return nil
+	}
+	unsupported := func(capability string) error {
+		return &groqerr.ErrUnsupportedByModel{
+			Model:      string(request.Model),
+			Capability: capability,
+		}
+	}
+	if !info.Vision {
+		for _, m := range request.Messages {
+			for _, part := range m.MultiContent {
+				if part.Type == ChatMessagePartTypeImageURL {
+					return unsupported("vision")
+				}
+			}
+		}
+	}
+	if !info.Tools && len(request.Tools) > 0 {
+		return unsupported("tools")
+	}
+	if !info.JSONMode && request.ResponseFormat != nil &&
+		request.ResponseFormat.Type != "" &&
+		request.ResponseFormat.Type != FormatText {
+		return unsupported("json mode")
+	}
+	if info.MaxCompletionTokens > 0 &&
+		request.MaxTokens > info.MaxCompletionTokens {
+		return unsupported("max tokens above the completion limit")
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelInfo(t *testing.T) {
+	a := assert.New(t)
+	info, ok := ModelInfo(ModelLlama3211BVisionPreview)
+	a.True(ok)
+	a.True(info.Vision)
+	a.Equal(8192, info.ContextWindow)
+
+	info, ok = ModelInfo(ModelLlama3170BVersatile)
+	a.True(ok)
+	a.True(info.Deprecated)
+	a.Equal(Model(ModelLlama3370BVersatile), info.Replacement)
+
+	info, ok = ModelInfo(ModelLlama318BInstant)
+	a.True(ok)
+	a.Equal(131072, info.ContextWindow)
+	a.Equal(8192, info.MaxCompletionTokens)
+
+	info, ok = ModelInfo(ModelLlamaGuard38B)
+	a.True(ok)
+	a.False(info.Tools)
+
+	_, ok = ModelInfo(ChatModel("not-a-model"))
+	a.False(ok)
+}
+
+func TestValidateChatRequest(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	var calls int
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			handleModerationEndpoint(w, r)
+		},
+	)
+	image := []ChatCompletionMessage{{
+		Role: RoleUser,
+		MultiContent: []ChatMessagePart{{
+			Type:     ChatMessagePartTypeImageURL,
+			ImageURL: &ChatMessageImageURL{URL: "https://example.com/a.png"},
+		}},
+	}}
+	text := []ChatCompletionMessage{{Role: RoleUser, Content: "Hello!"}}
+	testcases := []struct {
+		name       string
+		request    ChatCompletionRequest
+		capability string
+	}{
+		{
+			name:       "image to non vision model",
+			request:    ChatCompletionRequest{Model: ModelLlama38B8192, Messages: image},
+			capability: "vision",
+		},
+		{
+			name: "tools to non tool model",
+			request: ChatCompletionRequest{
+				Model:    ChatModel(ModelLlamaGuard38B),
+				Messages: text,
+				Tools:    []tools.Tool{{Type: tools.ToolTypeFunction}},
+			},
+			capability: "tools",
+		},
+		{
+			name: "max tokens above the limit",
+			request: ChatCompletionRequest{
+				Model:     ModelLlama38B8192,
+				Messages:  text,
+				MaxTokens: 100_000,
+			},
+			capability: "max tokens above the completion limit",
+		},
+		{
+			// the completion limit is well below the context window
+			name: "max tokens above the limit of a large context model",
+			request: ChatCompletionRequest{
+				Model:     ModelLlama318BInstant,
+				Messages:  text,
+				MaxTokens: 10_000,
+			},
+			capability: "max tokens above the completion limit",
+		},
+		{
+			name:    "image to vision model",
+			request: ChatCompletionRequest{Model: ModelLlama3211BVisionPreview, Messages: image},
+		},
+		{
+			name: "unknown model",
+			request: ChatCompletionRequest{
+				Model:    "not-a-model",
+				Messages: image,
+				Tools:    []tools.Tool{{Type: tools.ToolTypeFunction}},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls = 0
+			_, err := client.ChatCompletion(context.Background(), tc.request)
+			if tc.capability == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, calls)
+				return
+			}
+			var capErr *groqerr.ErrUnsupportedByModel
+			a.ErrorAs(err, &capErr)
+			a.Equal(tc.capability, capErr.Capability)
+			a.Zero(calls, "request should not be sent")
+		})
+	}
+}
@@ -39,12 +39,39 @@ type (
 		OwnedBy       string `json:"owned_by"`
 		Active        bool   `json:"active"`
 		ContextWindow int    `json:"context_window"`
+		// MaxCompletionTokens is the maximum number of tokens the model
+		// can generate.
+		//
+		// It defaults to the one of the capabilities table when not
+		// reported, and is left unknown (zero) otherwise.
+		MaxCompletionTokens int `json:"max_completion_tokens"`
+		// Vision is whether the model accepts image inputs.
+		Vision bool `json:"-"`
+		// Tools is whether the model supports tool use.
+		Tools bool `json:"-"`
+		// JSONMode is whether the model supports json response formats.
+		JSONMode bool `json:"-"`
+		// Replacement is the model replacing the model if it is
+		// deprecated.
+		Replacement string `json:"-"`
+		// Unlisted is whether the model is a chat model missing from the
+		// capabilities table, leaving it out of the model registry.
+		Unlisted bool `json:"-"`
+	}
+	// capability is the curated capabilities of a chat model, which the
+	// models endpoint does not report.
+	capability struct {
+		MaxCompletionTokens int
+		Vision              bool
+		Tools               bool
+		JSONMode            bool
 	}
 	// CategorizedModels is a struct that contains all the models.
 	CategorizedModels struct {
 		ChatModels       []ResponseModel
 		AudioModels      []ResponseModel
 		ModerationModels []ResponseModel
+		EmbeddingModels  []ResponseModel
 	}
 )
 
@@ -55,6 +82,47 @@ var (
 	outputs = []*template.Template{
 		template.New("models").Funcs(funcMap),
 		template.New("models_test").Funcs(funcMap),
+		template.New("model_registry").Funcs(funcMap),
+	}
+
+	// deprecations maps deprecated model ids to the ids of the models
+	// replacing them.
+	//
+	// https://console.groq.com/docs/deprecations
+	deprecations = map[string]string{
+		"gemma-7b-it":                           "gemma2-9b-it",
+		"llama-3.1-70b-versatile":               "llama-3.3-70b-versatile",
+		"llama3-groq-70b-8192-tool-use-preview": "llama-3.3-70b-versatile",
+		"llama3-groq-8b-8192-tool-use-preview":  "llama-3.1-8b-instant",
+	}
+
+	// capabilities maps the ids of chat models to their capabilities.
+	//
+	// Chat models missing from it are left out of the model registry, so
+	// that requests to them are not checked, until they are added.
+	//
+	// https://console.groq.com/docs/models
+	// https://console.groq.com/docs/tool-use
+	// https://console.groq.com/docs/vision
+	//
+	// The fields are in order MaxCompletionTokens, Vision, Tools and
+	// JSONMode, a zero MaxCompletionTokens being unknown.
+	capabilities = map[string]capability{
+		"gemma2-9b-it":                          {0, false, true, true},
+		"gemma-7b-it":                           {0, false, true, true},
+		"llama-3.1-70b-versatile":               {8192, false, true, true},
+		"llama-3.1-8b-instant":                  {8192, false, true, true},
+		"llama-3.2-11b-vision-preview":          {8192, true, true, true},
+		"llama-3.2-1b-preview":                  {8192, false, true, true},
+		"llama-3.2-3b-preview":                  {8192, false, true, true},
+		"llama-3.2-90b-vision-preview":          {8192, true, true, true},
+		"llama-3.3-70b-specdec":                 {8192, false, true, true},
+		"llama-3.3-70b-versatile":               {32768, false, true, true},
+		"llama3-70b-8192":                       {8192, false, true, true},
+		"llama3-8b-8192":                        {8192, false, true, true},
+		"llama3-groq-70b-8192-tool-use-preview": {8192, false, true, true},
+		"llama3-groq-8b-8192-tool-use-preview":  {8192, false, true, true},
+		"mixtral-8x7b-32768":                    {32768, false, true, true},
 	}
 
 	funcMap = template.FuncMap{
@@ -151,11 +219,31 @@ func (r *Response) Categorize() (CategorizedModels, error) {
 		return r.Data[i].Name < r.Data[j].Name
 	})
 	for _, model := range r.Data {
+		model.Replacement = deprecations[model.ID]
 		if model.ID == "llama-guard-3-8b" {
 			models.ModerationModels = append(models.ModerationModels, model)
 			continue
 		}
+		if strings.Contains(model.ID, "embed") {
+			models.EmbeddingModels = append(models.EmbeddingModels, model)
+			continue
+		}
 		if model.ContextWindow >= 1024 {
+			c, ok := capabilities[model.ID]
+			if !ok {
+				fmt.Fprintf(
+					os.Stderr,
+					"chat model %s is missing from the capabilities table\n",
+					model.ID,
+				)
+			}
+			if model.MaxCompletionTokens == 0 {
+				model.MaxCompletionTokens = c.MaxCompletionTokens
+			}
+			model.Vision = c.Vision
+			model.Tools = c.Tools
+			model.JSONMode = c.JSONMode
+			model.Unlisted = !ok
 			models.ChatModels = append(models.ChatModels, model)
 			continue
 		}
@@ -164,6 +252,16 @@ func (r *Response) Categorize() (CategorizedModels, error) {
 	return models, nil
 }
 
+// All returns the models of every category.
+func (c CategorizedModels) All() []ResponseModel {
+	all := make([]ResponseModel, 0, len(c.ChatModels)+len(c.AudioModels)+
+		len(c.ModerationModels)+len(c.EmbeddingModels))
+	all = append(all, c.ChatModels...)
+	all = append(all, c.AudioModels...)
+	all = append(all, c.ModerationModels...)
+	return append(all, c.EmbeddingModels...)
+}
+
 var (
 	// LowerCaseLettersCharset is a set of lower case letters.
 	LowerCaseLettersCharset = []rune("abcdefghijklmnopqrstuvwxyz")
@@ -19,8 +19,9 @@ func main() {
 
 func run(
 	ctx context.Context,
+	opts ...groq.Opts,
 ) error {
-	client, err := groq.NewClient(os.Getenv("GROQ_KEY"))
+	client, err := groq.NewClient(os.Getenv("GROQ_KEY"), opts...)
 	if err != nil {
 		return err
 	}
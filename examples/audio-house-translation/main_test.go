@@ -2,19 +2,23 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
 	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/cassette"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAudioHouseTranslation(t *testing.T) {
-	if len(os.Getenv("UNIT")) < 1 {
-		t.Skip("Skipping AudioHouseTranslation test")
-	}
-	ctx := context.Background()
-	err := run(ctx)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if os.Getenv("UNIT") == "" {
+		// the replayed cassette needs no key
+		t.Setenv("GROQ_KEY", "replay")
 	}
+	a := assert.New(t)
+	err := run(
+		context.Background(),
+		groq.WithClient(cassette.NewClient(t, "testdata/translation.json")),
+	)
+	a.NoError(err)
 }
@@ -32,9 +32,10 @@ func run(
 	ctx context.Context,
 	r io.Reader,
 	w io.Writer,
+	opts ...groq.Opts,
 ) error {
 	key := os.Getenv("GROQ_KEY")
-	client, err := groq.NewClient(key)
+	client, err := groq.NewClient(key, opts...)
 	if err != nil {
 		return err
 	}
@@ -86,6 +87,7 @@ func input(
 	if err != nil {
 		return err
 	}
+	defer output.Close()
 	fmt.Fprintln(writer, "\nai: ")
 	for {
 		response, err := output.Recv()
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/cassette"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatTerminal(t *testing.T) {
+	if os.Getenv("UNIT") == "" {
+		// the replayed cassette needs no key
+		t.Setenv("GROQ_KEY", "replay")
+	}
+	a := assert.New(t)
+	var out strings.Builder
+	err := run(
+		context.Background(),
+		strings.NewReader("Hello!\n"),
+		&out,
+		groq.WithClient(cassette.NewClient(t, "testdata/chat.json")),
+	)
+	// the chat ends with its input
+	a.ErrorIs(err, io.EOF)
+	a.Contains(out.String(), "ai: \nHello! How can I help you today?")
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/conneroisu/groq-go/pkg/builders"
 	"github.com/conneroisu/groq-go/pkg/groqerr"
@@ -27,9 +28,14 @@ type (
 
 		header             builders.Header
 		requestFormBuilder builders.FormBuilder
+		retryPolicy        RetryPolicy
+		limiter            *RateLimiter
+		strictSchemas      bool
+		middlewares        []Middleware
 
-		client *http.Client
-		logger *slog.Logger
+		client    *http.Client
+		logger    *slog.Logger
+		logPolicy LogPolicy
 	}
 	// Opts is a function that sets options for a Groq client.
 	Opts func(*Client)
@@ -46,10 +52,40 @@ func WithBaseURL(baseURL string) Opts {
 }
 
 // WithLogger sets the logger for the Groq client.
+//
+// The client logs the start and outcome of its calls, their token usage,
+// the lifecycle of streams, and its HTTP requests and retries. Nothing is
+// logged by default. Message contents and the API key are redacted unless
+// revealed through WithLogPolicy.
 func WithLogger(logger *slog.Logger) Opts {
 	return func(c *Client) { c.logger = logger }
 }
 
+// WithRetryPolicy sets the retry policy for the Groq client.
+//
+// Zero valued fields of the policy are replaced by the values of
+// DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Opts {
+	return func(c *Client) { c.retryPolicy = policy.withDefaults() }
+}
+
+// WithRateLimiter sets the client side rate limiter for the Groq client.
+//
+// Chat completion and moderation requests wait for the limiter to have
+// capacity before being sent.
+func WithRateLimiter(limiter *RateLimiter) Opts {
+	return func(c *Client) { c.limiter = limiter }
+}
+
+// WithStrictSchemas makes the Groq client rewrite the JSON schemas of
+// structured outputs to the subset supported by strict mode before sending
+// them, see schema.Schema.Strict.
+//
+// Use schema.Schema.Lint to report the constructs that would be rewritten.
+func WithStrictSchemas() Opts {
+	return func(c *Client) { c.strictSchemas = true }
+}
+
 // NewClient creates a new Groq client.
 func NewClient(groqAPIKey string, opts ...Opts) (*Client, error) {
 	if groqAPIKey == "" {
@@ -58,9 +94,10 @@ func NewClient(groqAPIKey string, opts ...Opts) (*Client, error) {
 	c := &Client{
 		groqAPIKey:         groqAPIKey,
 		client:             http.DefaultClient,
-		logger:             slog.Default(),
+		logger:             slog.New(slog.NewTextHandler(io.Discard, nil)),
 		baseURL:            groqAPIURLv1,
 		emptyMessagesLimit: 10,
+		retryPolicy:        DefaultRetryPolicy(),
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -84,7 +121,11 @@ func (c *Client) fullURL(suffix endpoint, setters ...fullURLOption) string {
 	return fmt.Sprintf("%s%s", baseURL, suffix)
 }
 
-func (c *Client) sendRequest(req *http.Request, v response) error {
+func (c *Client) sendRequest(
+	req *http.Request,
+	v response,
+	setters ...sendOption,
+) error {
 	req.Header.Set("Accept", "application/json")
 	// Check whether Content-Type is already set, Upload Files API requires
 	// Content-Type == multipart/form-data
@@ -92,7 +133,7 @@ func (c *Client) sendRequest(req *http.Request, v response) error {
 	if contentType == "" {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	res, err := c.client.Do(req)
+	res, err := c.do(req, setters...)
 	if err != nil {
 		return err
 	}
@@ -126,13 +167,16 @@ func (c *Client) handleErrorResp(resp *http.Response) error {
 func sendRequestStream[T streams.Streamer[ChatCompletionStreamResponse]](
 	client *Client,
 	req *http.Request,
+	idleTimeout time.Duration,
+	setters ...sendOption,
 ) (*streams.StreamReader[*ChatCompletionStreamResponse], error) {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "text/event-stream")
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Connection", "keep-alive")
-	resp, err := client.client.Do(
+	resp, err := client.do(
 		req,
+		setters...,
 	) //nolint:bodyclose // body is closed in stream.Close()
 	if err != nil {
 		return new(streams.StreamReader[*ChatCompletionStreamResponse]), err
@@ -140,8 +184,12 @@ func sendRequestStream[T streams.Streamer[ChatCompletionStreamResponse]](
 	if isFailureStatusCode(resp) {
 		return new(streams.StreamReader[*ChatCompletionStreamResponse]), client.handleErrorResp(resp)
 	}
+	body := resp.Body
+	if idleTimeout > 0 {
+		body = streams.NewIdleTimeoutReader(body, idleTimeout)
+	}
 	return streams.NewStreamReader[ChatCompletionStreamResponse](
-		resp.Body,
+		body,
 		resp.Header,
 		client.emptyMessagesLimit,
 	), nil
@@ -177,7 +225,7 @@ func decodeString(body io.Reader, output *string) error {
 }
 
 func withModel[
-	T ChatModel | AudioModel | ModerationModel,
+	T ChatModel | AudioModel | ModerationModel | EmbeddingModel,
 ](model T) fullURLOption {
 	return func(args *fullURLOptions) {
 		args.model = string(model)
@@ -3,16 +3,13 @@ package groq
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strings"
-	"time"
 
-	"github.com/conneroisu/groq-go/internal/schema"
 	"github.com/conneroisu/groq-go/pkg/builders"
-	"github.com/conneroisu/groq-go/pkg/groqerr"
 )
 
 const (
@@ -24,14 +21,44 @@ const (
 	translationsSuffix    endpoint = "/audio/translations"
 	embeddingsSuffix      endpoint = "/embeddings"
 	moderationsSuffix     endpoint = "/moderations"
+	modelsSuffix          endpoint = "/models"
 )
 
 // ChatCompletion method is an API call to create a chat completion.
 func (c *Client) ChatCompletion(
 	ctx context.Context,
 	request ChatCompletionRequest,
+) (ChatCompletionResponse, error) {
+	return invoke(
+		ctx,
+		c,
+		Call{
+			Operation: OperationChatCompletion,
+			Model:     string(request.Model),
+			Request:   &request,
+		},
+		func(ctx context.Context) (ChatCompletionResponse, error) {
+			return c.chatCompletion(ctx, request)
+		},
+	)
+}
+
+// chatCompletion creates a chat completion once the middlewares of the
+// client are done with the call.
+func (c *Client) chatCompletion(
+	ctx context.Context,
+	request ChatCompletionRequest,
 ) (response ChatCompletionResponse, err error) {
 	request.Stream = false
+	err = validateChatRequest(request)
+	if err != nil {
+		return
+	}
+	observe, err := c.limit(ctx, request)
+	if err != nil {
+		return
+	}
+	defer func() { observe(response.header, &response.Usage, err) }()
 	req, err := builders.NewRequest(
 		ctx,
 		c.header,
@@ -41,13 +68,11 @@ func (c *Client) ChatCompletion(
 	if err != nil {
 		return
 	}
-	err = c.sendRequest(req, &response)
-	reqErr, ok := err.(*groqerr.APIError)
-	if ok && (reqErr.HTTPStatusCode == http.StatusServiceUnavailable ||
-		reqErr.HTTPStatusCode == http.StatusInternalServerError) {
-		time.Sleep(request.RetryDelay)
-		return c.ChatCompletion(ctx, request)
-	}
+	err = c.sendRequest(
+		req,
+		&response,
+		withRetryDelay(request.RetryDelay),
+	)
 	return
 }
 
@@ -56,8 +81,41 @@ func (c *Client) ChatCompletion(
 func (c *Client) ChatCompletionStream(
 	ctx context.Context,
 	request ChatCompletionRequest,
+) (*ChatCompletionStream, error) {
+	return invoke(
+		ctx,
+		c,
+		Call{
+			Operation: OperationChatCompletionStream,
+			Model:     string(request.Model),
+			Request:   &request,
+		},
+		func(ctx context.Context) (*ChatCompletionStream, error) {
+			return c.chatCompletionStream(ctx, request)
+		},
+	)
+}
+
+// chatCompletionStream creates a streamed chat completion once the
+// middlewares of the client are done with the call.
+func (c *Client) chatCompletionStream(
+	ctx context.Context,
+	request ChatCompletionRequest,
 ) (stream *ChatCompletionStream, err error) {
 	request.Stream = true
+	err = validateChatRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	observe, err := c.limit(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			observe(nil, nil, err)
+		}
+	}()
 	req, err := builders.NewRequest(
 		ctx,
 		c.header,
@@ -70,96 +128,136 @@ func (c *Client) ChatCompletionStream(
 	if err != nil {
 		return nil, err
 	}
-	resp, err := sendRequestStream(c, req)
+	resp, err := sendRequestStream(
+		c,
+		req,
+		request.StreamIdleTimeout,
+		withRetryDelay(request.RetryDelay),
+	)
 	if err != nil {
 		return
 	}
-	return &ChatCompletionStream{
+	observe(resp.Header, nil, nil)
+	stream = &ChatCompletionStream{
 		StreamReader: resp,
-	}, nil
+	}
+	// the reserved tokens are corrected once the usage is streamed
+	var usage *Usage
+	stream.Observe(func(chunk *ChatCompletionStreamResponse, err error) {
+		switch {
+		case chunk != nil && chunk.Usage != nil:
+			usage = chunk.Usage
+		case chunk != nil && chunk.XGroq != nil && chunk.XGroq.Usage != nil:
+			usage = chunk.XGroq.Usage
+		case chunk == nil && usage != nil:
+			observe(nil, usage, nil)
+		}
+	})
+	return stream, nil
 }
 
 // ChatCompletionJSON method is an API call to create a chat completion
 // w/ object output.
+//
+// The reply is validated against the JSON schema of output, see
+// ChatCompletionRequest.RepairAttempts to have invalid replies corrected.
+//
+// Use Structured for a typed alternative returning the response.
 func (c *Client) ChatCompletionJSON(
 	ctx context.Context,
 	request ChatCompletionRequest,
 	output any,
 ) (err error) {
-	schema, err := schema.ReflectSchema(reflect.TypeOf(output))
-	if err != nil {
-		return err
-	}
-	request.ResponseFormat = &ChatResponseFormat{
-		JSONSchema: &JSONSchema{
-			Name:        schema.Title,
-			Description: schema.Description,
-			Schema:      *schema,
-			Strict:      true,
+	_, err = c.structured(
+		ctx,
+		request,
+		reflect.TypeOf(output),
+		func(i int) any {
+			if i == 0 {
+				return output
+			}
+			return nil
 		},
-		Type: FormatJSON,
-	}
-	response, err := c.ChatCompletion(ctx, request)
-	if err != nil {
-		reqErr, ok := err.(*groqerr.APIError)
-		if ok && (reqErr.HTTPStatusCode == http.StatusServiceUnavailable ||
-			reqErr.HTTPStatusCode == http.StatusInternalServerError) {
-			time.Sleep(request.RetryDelay)
-			return c.ChatCompletionJSON(ctx, request, output)
-		}
-	}
-	content := response.Choices[0].Message.Content
-	split := strings.Split(content, "```")
-	if len(split) > 1 {
-		content = split[1]
-	}
-	err = json.Unmarshal([]byte(content), &output)
-	if err != nil {
-		return fmt.Errorf(
-			"error unmarshalling response (%s) to output: %v",
-			response.ID,
-			err,
-		)
-	}
-	return
+	)
+	return err
 }
 
 // Moderate performs a moderation api call over a string.
 // Input can be an array or slice but a string will reduce the complexity.
+//
+// Use CreateModeration to also get the usage and rate limit of the call.
 func (c *Client) Moderate(
 	ctx context.Context,
 	messages []ChatCompletionMessage,
 	model ModerationModel,
-) (response []Moderation, err error) {
+) ([]Moderation, error) {
+	response, err := c.CreateModeration(ctx, ModerationRequest{
+		Messages: messages,
+		Model:    model,
+	})
+	return response.Categories, err
+}
+
+// CreateModeration moderates the messages of the request, returning the
+// categories of harmful content found along with the usage and rate limit
+// of the call.
+func (c *Client) CreateModeration(
+	ctx context.Context,
+	request ModerationRequest,
+) (ModerationResponse, error) {
+	return invoke(
+		ctx,
+		c,
+		Call{
+			Operation: OperationModeration,
+			Model:     string(request.Model),
+			Request:   &request,
+		},
+		func(ctx context.Context) (ModerationResponse, error) {
+			return c.moderate(ctx, request)
+		},
+	)
+}
+
+// moderate performs a moderation once the middlewares of the client are
+// done with the call.
+func (c *Client) moderate(
+	ctx context.Context,
+	request ModerationRequest,
+) (response ModerationResponse, err error) {
+	observe, err := c.limit(ctx, ChatCompletionRequest{
+		Model:    ChatModel(request.Model),
+		Messages: request.Messages,
+	})
+	if err != nil {
+		return
+	}
+	var resp ChatCompletionResponse
+	defer func() { observe(resp.header, &resp.Usage, err) }()
 	req, err := builders.NewRequest(
 		ctx,
 		c.header,
 		http.MethodPost,
-		c.fullURL(chatCompletionsSuffix, withModel(model)),
-		builders.WithBody(&struct {
-			Messages []ChatCompletionMessage `json:"messages"`
-			Model    ModerationModel         `json:"model,omitempty"`
-		}{
-			Messages: messages,
-			Model:    model,
-		}),
+		c.fullURL(chatCompletionsSuffix, withModel(request.Model)),
+		builders.WithBody(&request),
 	)
 	if err != nil {
 		return
 	}
-	var resp ChatCompletionResponse
 	err = c.sendRequest(req, &resp)
 	if err != nil {
 		return
 	}
+	response.Usage = resp.Usage
+	response.header = resp.header
 	if strings.Contains(resp.Choices[0].Message.Content, "unsafe") {
 		split := strings.Split(
 			strings.Split(resp.Choices[0].Message.Content, "\n")[1],
 			",",
 		)
 		for _, s := range split {
-			response = append(
-				response,
+			response.Categories = append(
+				response.Categories,
 				sectionMap[strings.TrimSpace(s)],
 			)
 		}
@@ -167,6 +265,47 @@ func (c *Client) Moderate(
 	return
 }
 
+// Embed calls the embeddings endpoint with the given request.
+//
+// Returns one embedding per input, in the order of the inputs.
+func (c *Client) Embed(
+	ctx context.Context,
+	request EmbeddingRequest,
+) (EmbeddingResponse, error) {
+	return invoke(
+		ctx,
+		c,
+		Call{
+			Operation: OperationEmbedding,
+			Model:     string(request.Model),
+			Request:   &request,
+		},
+		func(ctx context.Context) (EmbeddingResponse, error) {
+			return c.embed(ctx, request)
+		},
+	)
+}
+
+// embed creates embeddings once the middlewares of the client are done
+// with the call.
+func (c *Client) embed(
+	ctx context.Context,
+	request EmbeddingRequest,
+) (response EmbeddingResponse, err error) {
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodPost,
+		c.fullURL(embeddingsSuffix, withModel(request.Model)),
+		builders.WithBody(request),
+	)
+	if err != nil {
+		return
+	}
+	err = c.sendRequest(req, &response)
+	return
+}
+
 // Transcribe calls the transcriptions endpoint with the given request.
 //
 // Returns transcribed text in the response_format specified in the request.
@@ -174,7 +313,12 @@ func (c *Client) Transcribe(
 	ctx context.Context,
 	request AudioRequest,
 ) (AudioResponse, error) {
-	return c.callAudioAPI(ctx, request, transcriptionsSuffix)
+	return c.callAudioAPI(
+		ctx,
+		request,
+		OperationTranscription,
+		transcriptionsSuffix,
+	)
 }
 
 // Translate calls the translations endpoint with the given request.
@@ -184,13 +328,40 @@ func (c *Client) Translate(
 	ctx context.Context,
 	request AudioRequest,
 ) (AudioResponse, error) {
-	return c.callAudioAPI(ctx, request, translationsSuffix)
+	return c.callAudioAPI(
+		ctx,
+		request,
+		OperationTranslation,
+		translationsSuffix,
+	)
 }
 
 // callAudioAPI calls the audio API with the given request.
 //
 // Currently supports both the transcription and translation APIs.
 func (c *Client) callAudioAPI(
+	ctx context.Context,
+	request AudioRequest,
+	operation Operation,
+	endpointSuffix endpoint,
+) (AudioResponse, error) {
+	return invoke(
+		ctx,
+		c,
+		Call{
+			Operation: operation,
+			Model:     string(request.Model),
+			Request:   &request,
+		},
+		func(ctx context.Context) (AudioResponse, error) {
+			return c.sendAudioRequest(ctx, request, endpointSuffix)
+		},
+	)
+}
+
+// sendAudioRequest sends a request to the audio API once the middlewares
+// of the client are done with the call.
+func (c *Client) sendAudioRequest(
 	ctx context.Context,
 	request AudioRequest,
 	endpointSuffix endpoint,
@@ -225,3 +396,85 @@ func (c *Client) callAudioAPI(
 	}
 	return
 }
+
+// ListModels lists the models currently available through the api.
+func (c *Client) ListModels(ctx context.Context) (ModelList, error) {
+	return invoke(
+		ctx,
+		c,
+		Call{Operation: OperationListModels},
+		c.listModels,
+	)
+}
+
+// listModels lists the models once the middlewares of the client are done
+// with the call.
+func (c *Client) listModels(
+	ctx context.Context,
+) (response ModelList, err error) {
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodGet,
+		c.fullURL(modelsSuffix),
+	)
+	if err != nil {
+		return
+	}
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// GetModel retrieves the metadata of a single model.
+//
+// It can be used to check that a model, e.g. ChatModel, is still served
+// before sending requests to it.
+func (c *Client) GetModel(
+	ctx context.Context,
+	model Model,
+) (ModelMetadata, error) {
+	return invoke(
+		ctx,
+		c,
+		Call{
+			Operation: OperationGetModel,
+			Model:     string(model),
+			Request:   &model,
+		},
+		func(ctx context.Context) (ModelMetadata, error) {
+			return c.getModel(ctx, model)
+		},
+	)
+}
+
+// getModel retrieves the metadata of a model once the middlewares of the
+// client are done with the call.
+func (c *Client) getModel(
+	ctx context.Context,
+	model Model,
+) (response ModelMetadata, err error) {
+	req, err := builders.NewRequest(
+		ctx,
+		c.header,
+		http.MethodGet,
+		c.fullURL(modelsSuffix+endpoint("/"+url.PathEscape(string(model)))),
+	)
+	if err != nil {
+		return
+	}
+	err = c.sendRequest(req, &response)
+	return
+}
+
+// ValidateChatModel returns an error if the given chat model is unknown to
+// the api or is not active.
+func (c *Client) ValidateChatModel(ctx context.Context, model ChatModel) error {
+	m, err := c.GetModel(ctx, Model(model))
+	if err != nil {
+		return fmt.Errorf("validating model %s: %w", model, err)
+	}
+	if !m.Active {
+		return fmt.Errorf("model %s is not active", model)
+	}
+	return nil
+}
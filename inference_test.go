@@ -3,6 +3,8 @@ package groq
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/conneroisu/groq-go/pkg/builders"
 	"github.com/conneroisu/groq-go/internal/test"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
 	"github.com/conneroisu/groq-go/pkg/tools"
 	"github.com/stretchr/testify/assert"
 )
@@ -308,3 +311,145 @@ func (fb *mockFormBuilder) Close() error {
 func (fb *mockFormBuilder) FormDataContentType() string {
 	return ""
 }
+
+func TestEmbed(t *testing.T) {
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	vector := []float32{0.25, -1.5, 3}
+	server.RegisterHandler(
+		"/v1/embeddings",
+		func(w http.ResponseWriter, r *http.Request) {
+			var req EmbeddingRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var embedding any = vector
+			if req.EncodingFormat == EmbeddingEncodingFormatBase64 {
+				buf := new(bytes.Buffer)
+				err = binary.Write(buf, binary.LittleEndian, vector)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				embedding = base64.StdEncoding.EncodeToString(buf.Bytes())
+			}
+			data := make([]map[string]any, len(req.Input))
+			for i := range req.Input {
+				data[i] = map[string]any{
+					"object":    "embedding",
+					"index":     i,
+					"embedding": embedding,
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			err = json.NewEncoder(w).Encode(map[string]any{
+				"object": "list",
+				"data":   data,
+				"model":  req.Model,
+				"usage": map[string]int{
+					"prompt_tokens": 4,
+					"total_tokens":  4,
+				},
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		},
+	)
+	for _, format := range []EmbeddingEncodingFormat{
+		"",
+		EmbeddingEncodingFormatFloat,
+		EmbeddingEncodingFormatBase64,
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			a := assert.New(t)
+			resp, err := client.Embed(ctx, EmbeddingRequest{
+				Model:          "nomic-embed-text-v1_5",
+				Input:          []string{"hello", "world"},
+				EncodingFormat: format,
+			})
+			a.NoError(err)
+			a.Len(resp.Data, 2)
+			for i, embedding := range resp.Data {
+				a.Equal(i, embedding.Index)
+				a.Equal(vector, embedding.Embedding)
+			}
+			a.Equal(4, resp.Usage.TotalTokens)
+		})
+	}
+}
+
+func TestModels(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	models := []ModelMetadata{
+		{
+			ID:            Model(ModelLlama38B8192),
+			Object:        "model",
+			Created:       1693721698,
+			OwnedBy:       "Meta",
+			Active:        true,
+			ContextWindow: 8192,
+		},
+		{
+			ID:            Model(ModelGemma7BIt),
+			Object:        "model",
+			Created:       1693721698,
+			OwnedBy:       "Google",
+			ContextWindow: 8192,
+		},
+	}
+	server.RegisterHandler(
+		"/v1/models",
+		func(w http.ResponseWriter, r *http.Request) {
+			a.Equal(http.MethodGet, r.Method)
+			err := json.NewEncoder(w).Encode(map[string]any{
+				"object": "list",
+				"data":   models,
+			})
+			a.NoError(err)
+		},
+	)
+	server.RegisterHandler(
+		"/v1/models/*",
+		func(w http.ResponseWriter, r *http.Request) {
+			for _, m := range models {
+				if r.URL.Path == "/v1/models/"+string(m.ID) {
+					a.NoError(json.NewEncoder(w).Encode(m))
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+			_, err := w.Write([]byte(`{"error":{` +
+				`"message":"The model does not exist",` +
+				`"type":"invalid_request_error",` +
+				`"code":"model_not_found"}}`))
+			a.NoError(err)
+		},
+	)
+
+	list, err := client.ListModels(ctx)
+	a.NoError(err)
+	a.Len(list.Data, 2)
+	m, ok := list.Get(Model(ModelGemma7BIt))
+	a.True(ok)
+	a.Equal("Google", m.OwnedBy)
+	a.Equal(int64(1693721698), m.CreatedAt().Unix())
+
+	m, err = client.GetModel(ctx, Model(ModelLlama38B8192))
+	a.NoError(err)
+	a.Equal(8192, m.ContextWindow)
+	a.True(m.Active)
+
+	a.NoError(client.ValidateChatModel(ctx, ModelLlama38B8192))
+	a.Error(client.ValidateChatModel(ctx, ModelGemma7BIt))
+	err = client.ValidateChatModel(ctx, "not-a-model")
+	var apiErr *groqerr.APIError
+	a.ErrorAs(err, &apiErr)
+	a.Equal(http.StatusNotFound, apiErr.HTTPStatusCode)
+}
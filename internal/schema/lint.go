@@ -0,0 +1,303 @@
+package schema
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/conneroisu/groq-go/internal/omap"
+)
+
+type (
+	// Violation is a construct of a schema that the strict mode of
+	// structured outputs rejects.
+	Violation struct {
+		// Path is the JSON pointer to the violating keyword of the schema.
+		Path string
+		// Message describes the violation.
+		Message string
+	}
+	// Violations are the constructs of a schema that the strict mode of
+	// structured outputs rejects.
+	Violations []Violation
+	// strictRewriter rewrites a schema to the strict mode subset.
+	strictRewriter struct {
+		root *Schema
+		kept map[string]*Schema
+	}
+)
+
+// Error implements the error interface.
+func (v Violation) Error() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+// Error implements the error interface.
+func (v Violations) Error() string {
+	msgs := make([]string, len(v))
+	for i, violation := range v {
+		msgs[i] = violation.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Lint checks the schema against the subset of JSON Schema supported by the
+// strict mode of structured outputs.
+//
+// The root must be an object rather than a composition, references must
+// not be recursive, formats are not supported, and every object must
+// require all of its properties and disallow additional ones.
+func (t *Schema) Lint() Violations {
+	var violations Violations
+	report := func(path, format string, args ...any) {
+		violations = append(violations, Violation{
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	if len(t.OneOf) > 0 {
+		report("/oneOf", "oneOf is not supported at the root")
+	}
+	if len(t.AnyOf) > 0 {
+		report("/anyOf", "anyOf is not supported at the root")
+	}
+	if root := t.deref(t); root != nil && root.Type != "object" {
+		report("/type", "the root must be an object, got %q", root.Type)
+	}
+	var lint func(s *Schema, path string)
+	lint = func(s *Schema, path string) {
+		if s == nil || s.boolean != nil {
+			return
+		}
+		if s.Format != "" {
+			report(path+"/format", "format %q is not supported", s.Format)
+		}
+		if s.Ref != "" && t.resolve(s.Ref) == nil {
+			report(path+"/$ref", "unresolved reference %s", s.Ref)
+		}
+		if s.Type == "object" || s.Properties != nil {
+			a := s.AdditionalProperties
+			if a == nil || a.boolean == nil || *a.boolean {
+				report(
+					path+"/additionalProperties",
+					"additionalProperties must be false",
+				)
+			}
+			for pair := s.properties(); pair != nil; pair = pair.Next() {
+				if !slices.Contains(s.Required, pair.Key) {
+					report(
+						path+"/required",
+						"property %q must be required",
+						pair.Key,
+					)
+				}
+			}
+		}
+		for childPath, child := range s.subschemas() {
+			lint(child, path+childPath)
+		}
+	}
+	lint(t, "")
+	for _, name := range slices.Sorted(maps.Keys(t.Definitions)) {
+		lint(t.Definitions[name], "/$defs/"+escapePointer(name))
+	}
+	for _, name := range slices.Sorted(maps.Keys(t.Definitions)) {
+		t.lintCycles(
+			t.Definitions[name],
+			"/$defs/"+escapePointer(name),
+			[]string{"#/$defs/" + escapePointer(name)},
+			report,
+		)
+	}
+	return violations
+}
+
+// lintCycles reports the references of the schema back to the definitions
+// of the stack.
+func (t *Schema) lintCycles(
+	s *Schema,
+	path string,
+	stack []string,
+	report func(path, format string, args ...any),
+) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		if s.Ref == stack[0] {
+			report(path+"/$ref", "recursive reference to %s", s.Ref)
+			return
+		}
+		if slices.Contains(stack, s.Ref) {
+			return
+		}
+		t.lintCycles(t.resolve(s.Ref), path, append(stack, s.Ref), report)
+	}
+	for childPath, child := range s.subschemas() {
+		t.lintCycles(child, path+childPath, stack, report)
+	}
+}
+
+// Strict returns a copy of the schema rewritten to the subset of JSON
+// Schema supported by the strict mode of structured outputs.
+//
+// References are inlined, formats are dropped, and every object with
+// properties requires all of them and disallows additional ones. Objects
+// without properties, e.g. of maps, keep their additional properties and
+// are still reported by Lint, as are recursive references, which cannot be
+// inlined and are kept along with the definitions they reference.
+func (t *Schema) Strict() *Schema {
+	r := &strictRewriter{root: t, kept: map[string]*Schema{}}
+	s := r.rewrite(t, nil)
+	s.Definitions = nil
+	if len(r.kept) > 0 {
+		s.Definitions = r.kept
+	}
+	return s
+}
+
+// rewrite rewrites a schema, inlining the references not in the stack.
+func (r *strictRewriter) rewrite(s *Schema, stack []string) *Schema {
+	if s == nil || s.boolean != nil {
+		return s
+	}
+	if s.Ref != "" {
+		def := r.root.resolve(s.Ref)
+		if def == nil {
+			c := *s
+			return &c
+		}
+		if !slices.Contains(stack, s.Ref) {
+			return r.rewrite(def, append(stack, s.Ref))
+		}
+		name := unescapePointer(strings.TrimPrefix(s.Ref, "#/$defs/"))
+		if _, ok := r.kept[name]; !ok && s.Ref != "#" {
+			r.kept[name] = nil
+			r.kept[name] = r.rewrite(def, []string{s.Ref})
+		}
+		return &Schema{Ref: s.Ref}
+	}
+	c := *s
+	c.Definitions = nil
+	c.Format = ""
+	rewriteAll := func(schemas []*Schema) []*Schema {
+		if schemas == nil {
+			return nil
+		}
+		rewritten := make([]*Schema, len(schemas))
+		for i, s := range schemas {
+			rewritten[i] = r.rewrite(s, stack)
+		}
+		return rewritten
+	}
+	rewriteMap := func(schemas map[string]*Schema) map[string]*Schema {
+		if schemas == nil {
+			return nil
+		}
+		rewritten := make(map[string]*Schema, len(schemas))
+		for k, s := range schemas {
+			rewritten[k] = r.rewrite(s, stack)
+		}
+		return rewritten
+	}
+	c.AllOf = rewriteAll(s.AllOf)
+	c.AnyOf = rewriteAll(s.AnyOf)
+	c.OneOf = rewriteAll(s.OneOf)
+	c.PrefixItems = rewriteAll(s.PrefixItems)
+	c.Not = r.rewrite(s.Not, stack)
+	c.If = r.rewrite(s.If, stack)
+	c.Then = r.rewrite(s.Then, stack)
+	c.Else = r.rewrite(s.Else, stack)
+	c.Items = r.rewrite(s.Items, stack)
+	c.Contains = r.rewrite(s.Contains, stack)
+	c.PropertyNames = r.rewrite(s.PropertyNames, stack)
+	c.AdditionalProperties = r.rewrite(s.AdditionalProperties, stack)
+	c.PatternProperties = rewriteMap(s.PatternProperties)
+	c.DependentSchemas = rewriteMap(s.DependentSchemas)
+	if s.Properties != nil {
+		c.Properties = omap.New[string, *Schema]()
+		for pair := s.properties(); pair != nil; pair = pair.Next() {
+			c.Properties.Set(pair.Key, r.rewrite(pair.Value, stack))
+		}
+	}
+	// objects without properties, e.g. of maps, are left as they are
+	// since forbidding their additional properties would leave them empty
+	if c.Properties != nil {
+		c.AdditionalProperties = falseSchema
+		c.Required = nil
+		for pair := s.properties(); pair != nil; pair = pair.Next() {
+			c.Required = append(c.Required, pair.Key)
+		}
+	}
+	return &c
+}
+
+// deref resolves the references of a schema against the schema.
+func (t *Schema) deref(s *Schema) *Schema {
+	for seen := 0; s != nil && s.Ref != "" && seen < 32; seen++ {
+		s = t.resolve(s.Ref)
+	}
+	return s
+}
+
+// properties returns the first property of the schema, if any.
+func (t *Schema) properties() *omap.Pair[string, *Schema] {
+	if t.Properties == nil {
+		return nil
+	}
+	return t.Properties.Oldest()
+}
+
+// subschemas returns an iterator over the subschemas of the schema along
+// with their JSON pointer relative to the schema.
+//
+// Definitions are not included.
+func (t *Schema) subschemas() iter.Seq2[string, *Schema] {
+	return func(yield func(string, *Schema) bool) {
+		one := func(path string, s *Schema) bool {
+			return s == nil || yield(path, s)
+		}
+		all := func(path string, schemas []*Schema) bool {
+			for i, s := range schemas {
+				if !one(path+"/"+strconv.Itoa(i), s) {
+					return false
+				}
+			}
+			return true
+		}
+		named := func(path string, schemas map[string]*Schema) bool {
+			for _, k := range slices.Sorted(maps.Keys(schemas)) {
+				if !one(path+"/"+escapePointer(k), schemas[k]) {
+					return false
+				}
+			}
+			return true
+		}
+		for pair := t.properties(); pair != nil; pair = pair.Next() {
+			if !one("/properties/"+escapePointer(pair.Key), pair.Value) {
+				return
+			}
+		}
+		_ = one("/additionalProperties", t.AdditionalProperties) &&
+			named("/patternProperties", t.PatternProperties) &&
+			one("/propertyNames", t.PropertyNames) &&
+			named("/dependentSchemas", t.DependentSchemas) &&
+			one("/items", t.Items) &&
+			all("/prefixItems", t.PrefixItems) &&
+			one("/contains", t.Contains) &&
+			all("/allOf", t.AllOf) &&
+			all("/anyOf", t.AnyOf) &&
+			all("/oneOf", t.OneOf) &&
+			one("/not", t.Not) &&
+			one("/if", t.If) &&
+			one("/then", t.Then) &&
+			one("/else", t.Else)
+	}
+}
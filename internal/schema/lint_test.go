@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lintNode struct {
+	Name     string      `json:"name"`
+	Children []*lintNode `json:"children,omitempty"`
+}
+
+type lintEvent struct {
+	Title  string            `json:"title"`
+	At     time.Time         `json:"at"`
+	Note   string            `json:"note,omitempty"`
+	Labels map[string]string `json:"labels"`
+	Root   lintNode          `json:"root"`
+}
+
+func TestLint(t *testing.T) {
+	s, err := ReflectSchema(lintEvent{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, Violations{
+		{
+			Path:    "/$defs/lintEvent/properties/at/format",
+			Message: `format "date-time" is not supported`,
+		},
+		{
+			Path:    "/$defs/lintEvent/required",
+			Message: `property "note" must be required`,
+		},
+		{
+			Path:    "/$defs/lintEvent/properties/labels/additionalProperties",
+			Message: "additionalProperties must be false",
+		},
+		{
+			Path:    "/$defs/lintNode/required",
+			Message: `property "children" must be required`,
+		},
+		{
+			Path:    "/$defs/lintNode/properties/children/items/$ref",
+			Message: "recursive reference to #/$defs/lintNode",
+		},
+	}, s.Lint())
+
+	union := &Schema{OneOf: []*Schema{
+		{Type: "string"},
+		{Type: "object", AdditionalProperties: Bool(false)},
+	}}
+	assert.Equal(t, Violations{
+		{Path: "/oneOf", Message: "oneOf is not supported at the root"},
+		{Path: "/type", Message: `the root must be an object, got ""`},
+	}, union.Lint())
+}
+
+func TestStrict(t *testing.T) {
+	s, err := ReflectSchema(lintEvent{})
+	require.NoError(t, err)
+	strict := s.Strict()
+	b, err := json.Marshal(strict)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"title": {"type": "string"},
+			"at": {"type": "string"},
+			"note": {"type": "string"},
+			"labels": {
+				"type": "object",
+				"additionalProperties": {"type": "string"}
+			},
+			"root": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {
+						"type": "array",
+						"items": {"$ref": "#/$defs/lintNode"}
+					}
+				},
+				"additionalProperties": false,
+				"required": ["name", "children"]
+			}
+		},
+		"additionalProperties": false,
+		"required": ["title", "at", "note", "labels", "root"],
+		"$defs": {
+			"lintNode": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {
+						"type": "array",
+						"items": {"$ref": "#/$defs/lintNode"}
+					}
+				},
+				"additionalProperties": false,
+				"required": ["name", "children"]
+			}
+		}
+	}`, string(b))
+	// maps are kept rather than forced empty, and still reported
+	assert.Equal(t, Violations{
+		{
+			Path:    "/properties/labels/additionalProperties",
+			Message: "additionalProperties must be false",
+		},
+		{
+			Path:    "/$defs/lintNode/properties/children/items/$ref",
+			Message: "recursive reference to #/$defs/lintNode",
+		},
+	}, strict.Lint())
+	// the original schema is left untouched
+	assert.Len(t, s.Lint(), 5)
+
+	order, err := ReflectSchema(partialOrder{})
+	require.NoError(t, err)
+	assert.Empty(t, order.Strict().Definitions)
+	assert.Empty(t, order.Strict().Lint())
+}
@@ -0,0 +1,248 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// partialParser parses a possibly truncated JSON document against a schema.
+type partialParser struct {
+	root *Schema
+	data []byte
+	pos  int
+}
+
+// ParsePartial parses a possibly truncated JSON document, e.g. the content
+// of a streamed completion, into the values of the document that are
+// complete so far.
+//
+// Objects and arrays are returned with their completed members even when
+// they are not closed yet, while strings, numbers and literals are only
+// returned once closed. Properties unknown to the schema and closed values
+// of the wrong type are dropped, so that the result can always be decoded
+// into the type the schema was reflected from.
+//
+// It returns nil when no value is complete yet.
+func (t *Schema) ParsePartial(data []byte) any {
+	p := &partialParser{root: t, data: data}
+	v, keep, _ := p.value(t)
+	if !keep {
+		return nil
+	}
+	return v
+}
+
+// value parses the value at the current position, returning the completed
+// part of the value, whether it should be kept and whether the value is
+// complete.
+func (p *partialParser) value(s *Schema) (v any, keep, complete bool) {
+	s = p.deref(s)
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, false, false
+	}
+	switch c := p.data[p.pos]; {
+	case c == '{':
+		v, complete = p.object(s)
+		keep = true
+	case c == '[':
+		v, complete = p.array(s)
+		keep = true
+	case c == '"':
+		v, complete = p.string()
+		keep = complete
+	case c == '-' || (c >= '0' && c <= '9'):
+		v, complete = p.number()
+		keep = complete
+	default:
+		v, complete = p.literal()
+		keep = complete
+	}
+	if keep && s != nil && s.Type != "" {
+		switch v.(type) {
+		case map[string]any:
+			keep = s.Type == "object"
+		case []any:
+			keep = s.Type == "array"
+		default:
+			keep = isType(v, s.Type)
+		}
+	}
+	return v, keep, complete
+}
+
+// object parses an object, keeping the properties known to the schema.
+func (p *partialParser) object(s *Schema) (any, bool) {
+	m := map[string]any{}
+	p.pos++
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return m, false
+		}
+		switch p.data[p.pos] {
+		case '}':
+			p.pos++
+			return m, true
+		case ',':
+			p.pos++
+			continue
+		case '"':
+		default:
+			return m, false
+		}
+		key, ok := p.string()
+		if !ok {
+			return m, false
+		}
+		p.skipSpace()
+		if p.pos >= len(p.data) || p.data[p.pos] != ':' {
+			return m, false
+		}
+		p.pos++
+		prop, known := property(s, key.(string))
+		v, keep, complete := p.value(prop)
+		if known && keep {
+			m[key.(string)] = v
+		}
+		if !complete {
+			return m, false
+		}
+	}
+}
+
+// array parses an array, keeping its completed items.
+func (p *partialParser) array(s *Schema) (any, bool) {
+	a := []any{}
+	p.pos++
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return a, false
+		}
+		switch p.data[p.pos] {
+		case ']':
+			p.pos++
+			return a, true
+		case ',':
+			p.pos++
+			continue
+		}
+		var item *Schema
+		if s != nil {
+			item = s.Items
+			if len(a) < len(s.PrefixItems) {
+				item = s.PrefixItems[len(a)]
+			}
+		}
+		v, keep, complete := p.value(item)
+		if keep {
+			a = append(a, v)
+		}
+		if !complete {
+			return a, false
+		}
+	}
+}
+
+// string parses a string, which is complete once its closing quote is
+// read.
+func (p *partialParser) string() (any, bool) {
+	start := p.pos
+	for i := start + 1; i < len(p.data); i++ {
+		switch p.data[i] {
+		case '\\':
+			i++
+		case '"':
+			var s string
+			if json.Unmarshal(p.data[start:i+1], &s) != nil {
+				return nil, false
+			}
+			p.pos = i + 1
+			return s, true
+		}
+	}
+	p.pos = len(p.data)
+	return nil, false
+}
+
+// number parses a number, which is complete once a character following it
+// is read.
+func (p *partialParser) number() (any, bool) {
+	start := p.pos
+	for p.pos < len(p.data) &&
+		strings.IndexByte("+-.eE0123456789", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+	if p.pos >= len(p.data) {
+		return nil, false
+	}
+	var n json.Number
+	if json.Unmarshal(p.data[start:p.pos], &n) != nil {
+		return nil, false
+	}
+	return n, true
+}
+
+// literal parses true, false or null.
+func (p *partialParser) literal() (any, bool) {
+	for lit, v := range map[string]any{
+		"true":  true,
+		"false": false,
+		"null":  nil,
+	} {
+		if strings.HasPrefix(string(p.data[p.pos:]), lit) {
+			p.pos += len(lit)
+			return v, true
+		}
+	}
+	p.pos = len(p.data)
+	return nil, false
+}
+
+// skipSpace skips the whitespace at the current position.
+func (p *partialParser) skipSpace() {
+	for p.pos < len(p.data) &&
+		strings.IndexByte(" \t\r\n", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// deref resolves the reference of a schema, if any, and the schema of the
+// values of a nullable one.
+func (p *partialParser) deref(s *Schema) *Schema {
+	if s != nil && s.Type == "" && len(s.AnyOf) == 2 {
+		switch "null" {
+		case s.AnyOf[1].Type:
+			s = s.AnyOf[0]
+		case s.AnyOf[0].Type:
+			s = s.AnyOf[1]
+		}
+	}
+	for s != nil && s.Ref != "" {
+		ref := p.root.resolve(s.Ref)
+		if ref == nil || ref == s {
+			return s
+		}
+		s = ref
+	}
+	return s
+}
+
+// property returns the schema of a property of an object schema and
+// whether the property is allowed by it.
+func property(s *Schema, name string) (*Schema, bool) {
+	if s == nil {
+		return nil, true
+	}
+	if s.Properties != nil {
+		if prop, ok := s.Properties.Get(name); ok {
+			return prop, true
+		}
+	}
+	a := s.AdditionalProperties
+	if a != nil && a.boolean != nil && !*a.boolean {
+		return nil, false
+	}
+	return a, true
+}
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type partialItem struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+type partialOrder struct {
+	ID    int           `json:"id"`
+	Paid  bool          `json:"paid"`
+	Items []partialItem `json:"items"`
+	Note  string        `json:"note"`
+}
+
+func TestParsePartial(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{data: ``, want: `null`},
+		{data: `{`, want: `{}`},
+		{data: `{"id":12`, want: `{}`},
+		{data: `{"id":12,`, want: `{"id":12}`},
+		{data: `{"id":12,"paid":tr`, want: `{"id":12}`},
+		{data: `{"id":12,"paid":true,"items":[{"name":"te`,
+			want: `{"id":12,"items":[{}],"paid":true}`},
+		{data: `{"id":12,"items":[{"name":"tea","price":2.5},{"na`,
+			want: `{"id":12,"items":[{"name":"tea","price":2.5},{}]}`},
+		{data: `{"id":"12","unknown":1,"note":"a \"b\" é"}`,
+			want: `{"note":"a \"b\" é"}`},
+		{data: "```json\n{}", want: `null`},
+	}
+	s := ReflectInline(reflect.TypeFor[partialOrder]())
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			got, err := json.Marshal(s.ParsePartial([]byte(tt.data)))
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestParsePartialNullable(t *testing.T) {
+	type order struct {
+		Item *partialItem `json:"item"`
+	}
+	s := ReflectInline(reflect.TypeFor[order]())
+	got, err := json.Marshal(s.ParsePartial([]byte(
+		`{"item":{"name":"tea","price":"free","size":1`,
+	)))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"item":{"name":"tea"}}`, string(got))
+}
+
+func TestParsePartialRef(t *testing.T) {
+	s, err := ReflectSchema(partialOrder{})
+	assert.NoError(t, err)
+	got, err := json.Marshal(s.ParsePartial([]byte(
+		`{"items":[{"name":"tea","price":"free"},{"name":`,
+	)))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"items":[{"name":"tea"},{}]}`, string(got))
+}
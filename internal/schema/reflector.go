@@ -4,8 +4,8 @@ import "reflect"
 
 type (
 
-	// A reflector reflects values into a Schema.
-	reflector struct {
+	// A Reflector reflects values into a Schema.
+	Reflector struct {
 		// BaseSchemaID defines the URI that will be used as a base to determine
 		// Schema IDs for models. For example, a base Schema ID of `
 		// https://conneroh.com/schemas` when defined with a struct called
@@ -15,7 +15,7 @@ type (
 		// If no `BaseSchemaID` is provided, we'll take the type's complete
 		// package path and use that as a base instead. Set `Anonymous` to try
 		// if you do not want to include a schema ID.
-		BaseSchemaID schemaID
+		BaseSchemaID ID
 		// Anonymous when true will hide the auto-generated Schema ID and
 		// provide what is known as an "anonymous schema". As a rule, this is
 		// not recommended.
@@ -45,6 +45,10 @@ type (
 		// one tree. The list of type definitions (`$defs`) will not be
 		// included.
 		DoNotReference bool
+		// NullablePointers will cause the Reflector to allow null for the
+		// struct fields of pointer types, which encoding/json decodes to a
+		// nil pointer, through an anyOf with the null type.
+		NullablePointers bool
 		// ExpandedStruct when true will include the reflected type's definition
 		// in the root as opposed to a definition with a reference.
 		ExpandedStruct bool
@@ -59,7 +63,7 @@ type (
 		// to be referenced by their ID instead of being embedded into the
 		// current schema definitions. Reflected types will never be pointers,
 		// only underlying elements.
-		Lookup func(reflect.Type) schemaID
+		Lookup func(reflect.Type) ID
 		// Mapper is a function that can be used to map custom Go types to
 		// jsonschema schemas.
 		Mapper func(reflect.Type) *Schema
@@ -94,12 +98,12 @@ type (
 )
 
 // Reflect reflects to Schema from a value.
-func (r *reflector) Reflect(v any) *Schema {
+func (r *Reflector) Reflect(v any) *Schema {
 	return r.ReflectFromType(reflect.TypeOf(v))
 }
 
 // ReflectFromType generates root schema
-func (r *reflector) ReflectFromType(t reflect.Type) *Schema {
+func (r *Reflector) ReflectFromType(t reflect.Type) *Schema {
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem() // re-assign from pointer
 	}
@@ -118,7 +122,7 @@ func (r *reflector) ReflectFromType(t reflect.Type) *Schema {
 	if !r.Anonymous && s.ID == EmptyID {
 		baseSchemaID := r.BaseSchemaID
 		if baseSchemaID == EmptyID {
-			i := schemaID("https://" + t.PkgPath())
+			i := ID("https://" + t.PkgPath())
 			if err := i.Validate(); err == nil {
 				// it's okay to silently ignore URL errors
 				baseSchemaID = i
@@ -137,10 +141,10 @@ func (r *reflector) ReflectFromType(t reflect.Type) *Schema {
 
 // SetBaseSchemaID is a helper use to be able to set the reflectors base
 // schema ID from a string as opposed to then ID instance.
-func (r *reflector) SetBaseSchemaID(identifier string) {
-	r.BaseSchemaID = schemaID(identifier)
+func (r *Reflector) SetBaseSchemaID(identifier string) {
+	r.BaseSchemaID = ID(identifier)
 }
-func (r *reflector) refOrReflectTypeToSchema(
+func (r *Reflector) refOrReflectTypeToSchema(
 	definitions schemaDefinitions,
 	t reflect.Type,
 ) *Schema {
@@ -156,7 +160,7 @@ func (r *reflector) refOrReflectTypeToSchema(
 	}
 	return r.reflectTypeToSchemaWithID(definitions, t)
 }
-func (r *reflector) reflectTypeToSchemaWithID(
+func (r *Reflector) reflectTypeToSchemaWithID(
 	defs schemaDefinitions,
 	t reflect.Type,
 ) *Schema {
@@ -171,7 +175,7 @@ func (r *reflector) reflectTypeToSchemaWithID(
 	}
 	return s
 }
-func (r *reflector) reflectTypeToSchema(
+func (r *Reflector) reflectTypeToSchema(
 	definitions schemaDefinitions,
 	t reflect.Type,
 ) *Schema {
@@ -253,7 +257,7 @@ func (r *reflector) reflectTypeToSchema(
 	}
 	return st
 }
-func (r *reflector) reflectCustomSchema(
+func (r *Reflector) reflectCustomSchema(
 	definitions schemaDefinitions,
 	t reflect.Type,
 ) *Schema {
@@ -271,7 +275,7 @@ func (r *reflector) reflectCustomSchema(
 	}
 	return nil
 }
-func (r *reflector) reflectSchemaExtend(
+func (r *Reflector) reflectSchemaExtend(
 	definitions schemaDefinitions,
 	t reflect.Type,
 	s *Schema,
@@ -285,7 +289,7 @@ func (r *reflector) reflectSchemaExtend(
 	}
 	return s
 }
-func (r *reflector) reflectSliceOrArray(
+func (r *Reflector) reflectSliceOrArray(
 	definitions schemaDefinitions,
 	t reflect.Type,
 	st *Schema,
@@ -310,7 +314,7 @@ func (r *reflector) reflectSliceOrArray(
 	st.Type = "array"
 	st.Items = r.refOrReflectTypeToSchema(definitions, t.Elem())
 }
-func (r *reflector) reflectMap(
+func (r *Reflector) reflectMap(
 	definitions schemaDefinitions,
 	t reflect.Type,
 	st *Schema,
@@ -341,7 +345,7 @@ func (r *reflector) reflectMap(
 }
 
 // Reflects a struct to a JSON Schema type.
-func (r *reflector) reflectStruct(
+func (r *Reflector) reflectStruct(
 	definitions schemaDefinitions,
 	t reflect.Type,
 	s *Schema,
@@ -379,7 +383,7 @@ func (r *reflector) reflectStruct(
 	}
 }
 
-func (r *reflector) reflectStructFields(
+func (r *Reflector) reflectStructFields(
 	st *Schema,
 	definitions schemaDefinitions,
 	t reflect.Type,
@@ -443,6 +447,15 @@ func (r *reflector) reflectStructFields(
 					},
 				},
 			}
+		} else if r.NullablePointers && f.Type.Kind() == reflect.Ptr {
+			property = &Schema{
+				AnyOf: []*Schema{
+					property,
+					{
+						Type: "null",
+					},
+				},
+			}
 		}
 		st.Properties.Set(name, property)
 		if required {
@@ -462,7 +475,7 @@ func (r *reflector) reflectStructFields(
 	}
 }
 
-func (r *reflector) lookupComment(t reflect.Type, name string) string {
+func (r *Reflector) lookupComment(t reflect.Type, name string) string {
 	if r.CommentMap == nil {
 		return ""
 	}
@@ -475,7 +488,7 @@ func (r *reflector) lookupComment(t reflect.Type, name string) string {
 
 // addDefinition will append the provided schema. If needed, an ID and anchor
 // will also be added.
-func (r *reflector) addDefinition(
+func (r *Reflector) addDefinition(
 	definitions schemaDefinitions,
 	t reflect.Type,
 	s *Schema,
@@ -489,7 +502,7 @@ func (r *reflector) addDefinition(
 
 // refDefinition will provide a schema with a reference to an existing
 // definition.
-func (r *reflector) refDefinition(
+func (r *Reflector) refDefinition(
 	definitions schemaDefinitions,
 	t reflect.Type,
 ) *Schema {
@@ -507,7 +520,7 @@ func (r *reflector) refDefinition(
 		Ref: "#/$defs/" + name,
 	}
 }
-func (r *reflector) lookupID(t reflect.Type) schemaID {
+func (r *Reflector) lookupID(t reflect.Type) ID {
 	if r.Lookup != nil {
 		if t.Kind() == reflect.Ptr {
 			t = t.Elem()
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net"
 	"net/url"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,16 +21,109 @@ const (
 	// version is the JSON Schema version.
 	version = "https://json-schema.org/draft/2020-12/schema"
 	// EmptyID is used to explicitly define an ID with no value.
-	EmptyID schemaID = ""
+	EmptyID ID = ""
 )
 
 // ReflectSchema returns a schema from a value.
 func ReflectSchema(a any) (*Schema, error) {
-	r := &reflector{}
+	r := &Reflector{}
 	schema := r.ReflectFromType(reflect.TypeOf(a))
 	return schema, nil
 }
 
+// ReflectInline returns a self-contained, anonymous schema of a type with
+// the schemas of nested types inlined instead of referenced.
+func ReflectInline(t reflect.Type) *Schema {
+	r := &Reflector{
+		Anonymous:        true,
+		DoNotReference:   true,
+		NullablePointers: true,
+	}
+	return r.ReflectFromType(t)
+}
+
+// Bool returns the boolean schema accepting any value when v is true and
+// no value otherwise.
+func Bool(v bool) *Schema {
+	return &Schema{boolean: &v}
+}
+
+// Clone returns a deep copy of the schema, so that changes to the copy or
+// to any of its subschemas leave the schema untouched.
+//
+// Values of keywords such as enum or default are shared.
+func (t *Schema) Clone() *Schema {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	cloneAll := func(schemas []*Schema) []*Schema {
+		if schemas == nil {
+			return nil
+		}
+		cloned := make([]*Schema, len(schemas))
+		for i, s := range schemas {
+			cloned[i] = s.Clone()
+		}
+		return cloned
+	}
+	cloneMap := func(schemas map[string]*Schema) map[string]*Schema {
+		if schemas == nil {
+			return nil
+		}
+		cloned := make(map[string]*Schema, len(schemas))
+		for k, s := range schemas {
+			cloned[k] = s.Clone()
+		}
+		return cloned
+	}
+	c.Definitions = cloneMap(t.Definitions)
+	c.AllOf = cloneAll(t.AllOf)
+	c.AnyOf = cloneAll(t.AnyOf)
+	c.OneOf = cloneAll(t.OneOf)
+	c.PrefixItems = cloneAll(t.PrefixItems)
+	c.Not = t.Not.Clone()
+	c.If = t.If.Clone()
+	c.Then = t.Then.Clone()
+	c.Else = t.Else.Clone()
+	c.Items = t.Items.Clone()
+	c.Contains = t.Contains.Clone()
+	c.PropertyNames = t.PropertyNames.Clone()
+	c.AdditionalProperties = t.AdditionalProperties.Clone()
+	c.ContentSchema = t.ContentSchema.Clone()
+	c.PatternProperties = cloneMap(t.PatternProperties)
+	c.DependentSchemas = cloneMap(t.DependentSchemas)
+	if t.Properties != nil {
+		c.Properties = omap.New[string, *Schema]()
+		for pair := t.properties(); pair != nil; pair = pair.Next() {
+			c.Properties.Set(pair.Key, pair.Value.Clone())
+		}
+	}
+	if t.DependentRequired != nil {
+		c.DependentRequired = make(
+			map[string][]string,
+			len(t.DependentRequired),
+		)
+		for k, names := range t.DependentRequired {
+			c.DependentRequired[k] = slices.Clone(names)
+		}
+	}
+	c.Required = slices.Clone(t.Required)
+	c.Enum = slices.Clone(t.Enum)
+	c.Examples = slices.Clone(t.Examples)
+	c.Extras = maps.Clone(t.Extras)
+	for _, n := range []**uint64{
+		&c.MaxLength, &c.MinLength, &c.MaxItems, &c.MinItems,
+		&c.MaxContains, &c.MinContains, &c.MaxProperties, &c.MinProperties,
+	} {
+		if *n != nil {
+			v := **n
+			*n = &v
+		}
+	}
+	return &c
+}
+
 // Available Go defined types for JSON Schema Validation.
 //
 // https://datatracker.ietf.org/doc/html/draft-wright-json-schema-validation-00#section-7.3
@@ -73,7 +168,7 @@ type (
 		//
 		// The value of this field MUST be a string.  This string SHOULD be a
 		// URI.
-		ID schemaID `json:"$id,omitempty"`
+		ID ID `json:"$id,omitempty"`
 		// Anchor is the anchor of the schema as specified in section 8.2.2 of RFC
 		// draft-bhutton-json-schema-00.
 		//
@@ -853,9 +948,9 @@ type (
 	//
 	// RFC draft-wright-json-schema-validation-00, section 5.26
 	schemaDefinitions map[string]*Schema
-	// schemaID represents a Schema schemaID type which should always be a URI.
+	// ID represents a Schema ID type which should always be a URI.
 	// See draft-bhutton-json-schema-00 section 8.2.1
-	schemaID string
+	ID string
 	// customSchemaImpl is used to detect if the type provides it's own
 	// custom Schema Type definition to use instead. Very useful for situations
 	// where there are custom JSON Marshal and Unmarshal methods.
@@ -1274,13 +1369,13 @@ func parseUint(num string) *uint64 {
 	}
 	return &val
 }
-func (r *reflector) fieldNameTag() string {
+func (r *Reflector) fieldNameTag() string {
 	if r.FieldNameTag != "" {
 		return r.FieldNameTag
 	}
 	return "json"
 }
-func (r *reflector) reflectFieldName(
+func (r *Reflector) reflectFieldName(
 	f reflect.StructField,
 ) (string, bool, bool, bool) {
 	jsonTagString := f.Tag.Get(r.fieldNameTag())
@@ -1378,7 +1473,7 @@ func (t *Schema) MarshalJSON() ([]byte, error) {
 	b[len(b)-1] = ','
 	return append(b, m[1:]...), nil
 }
-func (r *reflector) typeName(t reflect.Type) string {
+func (r *Reflector) typeName(t reflect.Type) string {
 	if r.Namer != nil {
 		if name := r.Namer(t); name != "" {
 			return name
@@ -1432,7 +1527,7 @@ func newProperties() *omap.OrderedMap[string, *Schema] {
 // Validate is used to check if the ID looks like a proper schema.
 // This is done by parsing the ID as a URL and checking it has all the
 // relevant parts.
-func (i schemaID) Validate() error {
+func (i ID) Validate() error {
 	u, err := url.Parse(string(i))
 	if err != nil {
 		return fmt.Errorf("invalid URL: %w", err)
@@ -1453,34 +1548,34 @@ func (i schemaID) Validate() error {
 }
 
 // Anchor sets the anchor part of the schema URI.
-func (i schemaID) Anchor(name string) schemaID {
+func (i ID) Anchor(name string) ID {
 	b := i.Base()
-	return schemaID(string(b) + "#" + name)
+	return ID(string(b) + "#" + name)
 }
 
 // Def adds or replaces a definition identifier.
-func (i schemaID) Def(name string) schemaID {
+func (i ID) Def(name string) ID {
 	b := i.Base()
-	return schemaID(string(b) + "#/$defs/" + name)
+	return ID(string(b) + "#/$defs/" + name)
 }
 
 // Add appends the provided path to the id, and removes any
 // anchor data that might be there.
-func (i schemaID) Add(path string) schemaID {
+func (i ID) Add(path string) ID {
 	b := i.Base()
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	return schemaID(string(b) + path)
+	return ID(string(b) + path)
 }
 
 // Base removes any anchor information from the schema
-func (i schemaID) Base() schemaID {
+func (i ID) Base() ID {
 	s := string(i)
 	li := strings.LastIndex(s, "#")
 	if li != -1 {
 		s = s[0:li]
 	}
 	s = strings.TrimRight(s, "/")
-	return schemaID(s)
+	return ID(s)
 }
@@ -0,0 +1,406 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// ValidationError is a violation of a schema by a JSON instance.
+	ValidationError struct {
+		// Path is the JSON pointer to the violating value of the
+		// instance.
+		Path string
+		// Message describes the violation.
+		Message string
+	}
+	// ValidationErrors are the violations of a schema by a JSON instance.
+	ValidationErrors []ValidationError
+)
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	path := e.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, e.Message)
+}
+
+// Error implements the error interface.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate validates a JSON document against the schema.
+//
+// It checks the type, enum, const, numeric bounds, string lengths and
+// patterns, array items and bounds, required, additional and nested
+// properties, and the composition keywords of the schema. References to
+// the definitions of the schema are followed, while formats are treated
+// as annotations.
+//
+// The returned error is a ValidationErrors when the document is valid JSON
+// that violates the schema.
+func (t *Schema) Validate(data []byte) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	err := d.Decode(&v)
+	if err != nil {
+		return err
+	}
+	if d.More() {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	var errs ValidationErrors
+	t.validate(t, v, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate validates a decoded JSON value against the schema, appending
+// the violations to errs.
+func (t *Schema) validate(
+	root *Schema,
+	v any,
+	path string,
+	errs *ValidationErrors,
+) {
+	if t == nil {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, ValidationError{
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	if t.boolean != nil {
+		if !*t.boolean {
+			fail("no value is allowed")
+		}
+		return
+	}
+	if t.Ref != "" {
+		ref := root.resolve(t.Ref)
+		if ref == nil {
+			fail("unresolved reference %s", t.Ref)
+			return
+		}
+		ref.validate(root, v, path, errs)
+	}
+	if t.Type != "" && !isType(v, t.Type) {
+		fail("expected %s, got %s", t.Type, typeOf(v))
+		return
+	}
+	if len(t.Enum) > 0 && !slices.ContainsFunc(t.Enum, func(e any) bool {
+		return jsonEqual(e, v)
+	}) {
+		fail("value %s is not one of %s", marshal(v), marshal(t.Enum))
+	}
+	if t.Const != nil && !jsonEqual(t.Const, v) {
+		fail("value %s is not %s", marshal(v), marshal(t.Const))
+	}
+	switch v := v.(type) {
+	case json.Number:
+		t.validateNumber(v, fail)
+	case string:
+		t.validateString(v, fail)
+	case []any:
+		t.validateArray(root, v, path, errs, fail)
+	case map[string]any:
+		t.validateObject(root, v, path, errs, fail)
+	}
+	t.validateComposition(root, v, path, errs, fail)
+}
+
+// validateNumber validates the numeric keywords of the schema.
+func (t *Schema) validateNumber(v json.Number, fail func(string, ...any)) {
+	f, err := v.Float64()
+	if err != nil {
+		fail("invalid number %s", v)
+		return
+	}
+	bound := func(n json.Number) (float64, bool) {
+		if n == "" {
+			return 0, false
+		}
+		b, err := n.Float64()
+		return b, err == nil
+	}
+	if b, ok := bound(t.Minimum); ok && f < b {
+		fail("%s is less than the minimum of %s", v, t.Minimum)
+	}
+	if b, ok := bound(t.Maximum); ok && f > b {
+		fail("%s is greater than the maximum of %s", v, t.Maximum)
+	}
+	if b, ok := bound(t.ExclusiveMinimum); ok && f <= b {
+		fail("%s is not greater than %s", v, t.ExclusiveMinimum)
+	}
+	if b, ok := bound(t.ExclusiveMaximum); ok && f >= b {
+		fail("%s is not less than %s", v, t.ExclusiveMaximum)
+	}
+	if b, ok := bound(t.MultipleOf); ok && b != 0 {
+		q := f / b
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("%s is not a multiple of %s", v, t.MultipleOf)
+		}
+	}
+}
+
+// validateString validates the string keywords of the schema.
+func (t *Schema) validateString(v string, fail func(string, ...any)) {
+	n := uint64(utf8.RuneCountInString(v))
+	if t.MinLength != nil && n < *t.MinLength {
+		fail("length %d is less than the minimum of %d", n, *t.MinLength)
+	}
+	if t.MaxLength != nil && n > *t.MaxLength {
+		fail("length %d is greater than the maximum of %d", n, *t.MaxLength)
+	}
+	if t.Pattern != "" {
+		re, err := regexp.Compile(t.Pattern)
+		if err != nil {
+			fail("invalid pattern %q: %v", t.Pattern, err)
+		} else if !re.MatchString(v) {
+			fail("%q does not match the pattern %q", v, t.Pattern)
+		}
+	}
+}
+
+// validateArray validates the array keywords of the schema.
+func (t *Schema) validateArray(
+	root *Schema,
+	v []any,
+	path string,
+	errs *ValidationErrors,
+	fail func(string, ...any),
+) {
+	n := uint64(len(v))
+	if t.MinItems != nil && n < *t.MinItems {
+		fail("%d items are less than the minimum of %d", n, *t.MinItems)
+	}
+	if t.MaxItems != nil && n > *t.MaxItems {
+		fail("%d items are more than the maximum of %d", n, *t.MaxItems)
+	}
+	if t.UniqueItems {
+		for i := range v {
+			for j := range i {
+				if jsonEqual(v[i], v[j]) {
+					fail("items %d and %d are equal", j, i)
+				}
+			}
+		}
+	}
+	for i, item := range v {
+		itemPath := path + "/" + strconv.Itoa(i)
+		if i < len(t.PrefixItems) {
+			t.PrefixItems[i].validate(root, item, itemPath, errs)
+			continue
+		}
+		t.Items.validate(root, item, itemPath, errs)
+	}
+}
+
+// validateObject validates the object keywords of the schema.
+func (t *Schema) validateObject(
+	root *Schema,
+	v map[string]any,
+	path string,
+	errs *ValidationErrors,
+	fail func(string, ...any),
+) {
+	n := uint64(len(v))
+	if t.MinProperties != nil && n < *t.MinProperties {
+		fail("%d properties are less than the minimum of %d",
+			n, *t.MinProperties)
+	}
+	if t.MaxProperties != nil && n > *t.MaxProperties {
+		fail("%d properties are more than the maximum of %d",
+			n, *t.MaxProperties)
+	}
+	for _, name := range t.Required {
+		if _, ok := v[name]; !ok {
+			fail("missing required property %q", name)
+		}
+	}
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		keyPath := path + "/" + escapePointer(key)
+		matched := false
+		if t.Properties != nil {
+			if s, ok := t.Properties.Get(key); ok {
+				s.validate(root, v[key], keyPath, errs)
+				matched = true
+			}
+		}
+		for pattern, s := range t.PatternProperties {
+			re, err := regexp.Compile(pattern)
+			if err == nil && re.MatchString(key) {
+				s.validate(root, v[key], keyPath, errs)
+				matched = true
+			}
+		}
+		if matched || t.AdditionalProperties == nil {
+			continue
+		}
+		a := t.AdditionalProperties
+		if a.boolean != nil && !*a.boolean {
+			fail("additional property %q is not allowed", key)
+			continue
+		}
+		a.validate(root, v[key], keyPath, errs)
+	}
+}
+
+// validateComposition validates the composition keywords of the schema.
+func (t *Schema) validateComposition(
+	root *Schema,
+	v any,
+	path string,
+	errs *ValidationErrors,
+	fail func(string, ...any),
+) {
+	valid := func(s *Schema) bool {
+		var sub ValidationErrors
+		s.validate(root, v, path, &sub)
+		return len(sub) == 0
+	}
+	for _, s := range t.AllOf {
+		s.validate(root, v, path, errs)
+	}
+	if len(t.AnyOf) > 0 && !slices.ContainsFunc(t.AnyOf, valid) {
+		// a single schema of the type of the value, e.g. of a nullable
+		// property, reports its own violations
+		typed := slices.DeleteFunc(slices.Clone(t.AnyOf), func(s *Schema) bool {
+			return s.Type != "" && !isType(v, s.Type)
+		})
+		if len(typed) == 1 {
+			typed[0].validate(root, v, path, errs)
+		} else {
+			fail("value does not match any of the schemas of anyOf")
+		}
+	}
+	if len(t.OneOf) > 0 {
+		matches := 0
+		for _, s := range t.OneOf {
+			if valid(s) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("value matches %d of the schemas of oneOf", matches)
+		}
+	}
+	if t.Not != nil && valid(t.Not) {
+		fail("value matches the schema of not")
+	}
+	if t.If != nil {
+		if valid(t.If) {
+			t.Then.validate(root, v, path, errs)
+		} else {
+			t.Else.validate(root, v, path, errs)
+		}
+	}
+}
+
+// resolve resolves a reference to the schema or one of its definitions.
+func (t *Schema) resolve(ref string) *Schema {
+	if ref == "#" {
+		return t
+	}
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil
+	}
+	return t.Definitions[unescapePointer(name)]
+}
+
+// isType returns whether a decoded JSON value is of the given JSON type.
+func isType(v any, typ string) bool {
+	if typ == "integer" {
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	}
+	got := typeOf(v)
+	return got == typ || (typ == "number" && got == "integer")
+}
+
+// typeOf returns the JSON type of a decoded JSON value.
+func typeOf(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonEqual returns whether two values are equal once encoded to JSON.
+func jsonEqual(a, b any) bool {
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// normalize round-trips a value through JSON so that numbers are compared
+// by value.
+func normalize(v any) any {
+	var n any
+	if json.Unmarshal([]byte(marshal(v)), &n) != nil {
+		return v
+	}
+	return n
+}
+
+// marshal encodes a value to JSON for messages.
+func marshal(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// escapePointer escapes a reference token of a JSON pointer.
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// unescapePointer unescapes a reference token of a JSON pointer.
+func unescapePointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
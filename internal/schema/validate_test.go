@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validateAddress struct {
+	City string `json:"city" jsonschema:"minLength=2,pattern=^[A-Z]"`
+}
+
+type validatePerson struct {
+	Name    string           `json:"name"`
+	Age     int              `json:"age" jsonschema:"minimum=0,maximum=150"`
+	Role    string           `json:"role" jsonschema:"enum=admin,enum=user"`
+	Tags    []string         `json:"tags,omitempty" jsonschema:"maxItems=2"`
+	Address *validateAddress `json:"address,omitempty"`
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		errs ValidationErrors
+	}{
+		{
+			name: "valid",
+			data: `{"name":"Ada","age":36,"role":"admin",` +
+				`"tags":["a"],"address":{"city":"London"}}`,
+		},
+		{
+			name: "required",
+			data: `{"name":"Ada","age":36}`,
+			errs: ValidationErrors{
+				{Path: "", Message: `missing required property "role"`},
+			},
+		},
+		{
+			name: "types",
+			data: `{"name":1,"age":3.5,"role":"user"}`,
+			errs: ValidationErrors{
+				{Path: "/age", Message: "expected integer, got number"},
+				{Path: "/name", Message: "expected string, got integer"},
+			},
+		},
+		{
+			name: "enum and bounds",
+			data: `{"name":"Ada","age":200,"role":"root",` +
+				`"tags":["a","b","c"]}`,
+			errs: ValidationErrors{
+				{Path: "/age", Message: "200 is greater than the maximum of 150"},
+				{Path: "/role", Message: `value "root" is not one of ["admin","user"]`},
+				{Path: "/tags", Message: "3 items are more than the maximum of 2"},
+			},
+		},
+		{
+			name: "nested",
+			data: `{"name":"Ada","age":36,"role":"user",` +
+				`"address":{"city":"london","zip":"E1"}}`,
+			errs: ValidationErrors{
+				{Path: "/address", Message: `additional property "zip" is not allowed`},
+				{Path: "/address/city", Message: `"london" does not match the pattern "^[A-Z]"`},
+			},
+		},
+		{
+			name: "additional",
+			data: `{"name":"Ada","age":36,"role":"user","email":"a@b.c"}`,
+			errs: ValidationErrors{
+				{Path: "", Message: `additional property "email" is not allowed`},
+			},
+		},
+	}
+	for _, ref := range []bool{false, true} {
+		var s *Schema
+		if ref {
+			var err error
+			s, err = ReflectSchema(validatePerson{})
+			require.NoError(t, err)
+		} else {
+			s = ReflectInline(reflect.TypeFor[validatePerson]())
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := s.Validate([]byte(tt.data))
+				if tt.errs == nil {
+					assert.NoError(t, err)
+					return
+				}
+				var errs ValidationErrors
+				require.ErrorAs(t, err, &errs)
+				assert.ElementsMatch(t, tt.errs, errs)
+			})
+		}
+	}
+}
+
+func TestValidateSyntax(t *testing.T) {
+	s := ReflectInline(reflect.TypeFor[validatePerson]())
+	err := s.Validate([]byte(`{"name":`))
+	assert.Error(t, err)
+	var errs ValidationErrors
+	assert.NotErrorAs(t, err, &errs)
+	assert.Error(t, s.Validate([]byte(`{} {}`)))
+}
+
+func TestValidationErrorsError(t *testing.T) {
+	errs := ValidationErrors{
+		{Path: "", Message: "missing required property \"a\""},
+		{Path: "/b~1c", Message: "expected string, got integer"},
+	}
+	assert.Equal(
+		t,
+		`/: missing required property "a"; `+
+			`/b~1c: expected string, got integer`,
+		errs.Error(),
+	)
+}
+
+func TestValidateNullablePointers(t *testing.T) {
+	s := ReflectInline(reflect.TypeFor[validatePerson]())
+	assert.NoError(t, s.Validate([]byte(
+		`{"name":"Ada","age":36,"role":"user","address":null}`,
+	)))
+	err := s.Validate([]byte(`{"name":null,"age":36,"role":"user"}`))
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, ValidationErrors{
+		{Path: "/name", Message: "expected string, got null"},
+	}, errs)
+}
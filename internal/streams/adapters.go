@@ -0,0 +1,166 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+type (
+	// Broadcaster fans the responses of a stream out to several
+	// subscribers.
+	//
+	// Each subscriber buffers up to a fixed number of responses. A
+	// subscriber with a full buffer holds the others back until it catches
+	// up or unsubscribes, so memory stays bounded.
+	Broadcaster[T any] struct {
+		stream *StreamReader[T]
+		buffer int
+
+		mu   sync.Mutex
+		subs []*subscriber[T]
+		done bool
+	}
+	subscriber[T any] struct {
+		ch     chan T
+		cancel chan struct{}
+		once   sync.Once
+	}
+)
+
+// Chan receives the responses of the stream in a goroutine and sends them
+// on the returned channel.
+//
+// The response channel is closed when the stream ends, after which the
+// error channel receives the error that ended it, nil at the end of the
+// stream. The stream is closed once done, including when ctx is cancelled.
+func (stream *StreamReader[T]) Chan(
+	ctx context.Context,
+) (<-chan T, <-chan error) {
+	out := make(chan T)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer stream.Close()
+		errs <- stream.pump(ctx, func(response T) error {
+			select {
+			case out <- response:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(out)
+	}()
+	return out, errs
+}
+
+// pump receives the responses of the stream and passes them to fn until
+// the stream ends, fn fails or ctx is cancelled.
+//
+// It returns nil at the end of the stream.
+func (stream *StreamReader[T]) pump(
+	ctx context.Context,
+	fn func(T) error,
+) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		response, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(response); err != nil {
+			return err
+		}
+	}
+}
+
+// NewBroadcaster creates a new broadcaster of the stream buffering up to
+// buffer responses per subscriber.
+func NewBroadcaster[T any](
+	stream *StreamReader[T],
+	buffer int,
+) *Broadcaster[T] {
+	return &Broadcaster[T]{stream: stream, buffer: buffer}
+}
+
+// Subscribe subscribes to the responses of the stream.
+//
+// Subscribers joining after Run started miss the responses sent before.
+// The channel is closed when the stream ends or cancel is called, which
+// must be done when the subscriber stops reading.
+func (b *Broadcaster[T]) Subscribe() (responses <-chan T, cancel func()) {
+	s := &subscriber[T]{
+		ch:     make(chan T, b.buffer),
+		cancel: make(chan struct{}),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.done {
+		close(s.ch)
+		return s.ch, func() {}
+	}
+	b.subs = append(b.subs, s)
+	return s.ch, func() { s.once.Do(func() { close(s.cancel) }) }
+}
+
+// Run receives the responses of the stream and sends them to the
+// subscribers until the stream ends or ctx is cancelled.
+//
+// It closes the stream and the channels of the subscribers before
+// returning the error that ended the stream, nil at the end of the stream.
+func (b *Broadcaster[T]) Run(ctx context.Context) error {
+	defer b.stream.Close()
+	defer b.close()
+	return b.stream.pump(ctx, func(response T) error {
+		b.mu.Lock()
+		subs := append([]*subscriber[T](nil), b.subs...)
+		b.mu.Unlock()
+		for _, s := range subs {
+			select {
+			case <-s.cancel:
+				b.unsubscribe(s)
+				continue
+			default:
+			}
+			select {
+			case s.ch <- response:
+			case <-s.cancel:
+				b.unsubscribe(s)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// unsubscribe removes a subscriber and closes its channel.
+func (b *Broadcaster[T]) unsubscribe(s *subscriber[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, sub := range b.subs {
+		if sub == s {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			close(s.ch)
+			return
+		}
+	}
+}
+
+// close closes the channels of all subscribers.
+func (b *Broadcaster[T]) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.done = true
+	for _, s := range b.subs {
+		close(s.ch)
+	}
+	b.subs = nil
+}
@@ -0,0 +1,81 @@
+package streams_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/stretchr/testify/assert"
+)
+
+// newChunkStream creates a stream of n chunks with ids 0 to n-1.
+func newChunkStream(
+	n int,
+) *streams.StreamReader[*groq.ChatCompletionStreamResponse] {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, "data: {\"id\":\"%d\"}\n\n", i)
+	}
+	b.WriteString("data: [DONE]\n\n")
+	return streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		io.NopCloser(bytes.NewBufferString(b.String())),
+		nil,
+		10,
+	)
+}
+
+func TestStreamReaderChan(t *testing.T) {
+	a := assert.New(t)
+	chunks, errs := newChunkStream(3).Chan(context.Background())
+	var ids []string
+	for chunk := range chunks {
+		ids = append(ids, chunk.ID)
+	}
+	a.Equal([]string{"0", "1", "2"}, ids)
+	a.NoError(<-errs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, errs = newChunkStream(3).Chan(ctx)
+	<-chunks
+	cancel()
+	a.ErrorIs(<-errs, context.Canceled)
+}
+
+func TestBroadcaster(t *testing.T) {
+	a := assert.New(t)
+	b := streams.NewBroadcaster(newChunkStream(10), 2)
+	var (
+		wg  sync.WaitGroup
+		got [3][]string
+	)
+	for i := range got {
+		chunks, cancel := b.Subscribe()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer cancel()
+			for chunk := range chunks {
+				got[i] = append(got[i], chunk.ID)
+				// the last subscriber leaves early
+				if i == 2 && len(got[i]) == 3 {
+					return
+				}
+			}
+		}()
+	}
+	a.NoError(b.Run(context.Background()))
+	wg.Wait()
+	a.Len(got[0], 10)
+	a.Equal(got[0], got[1])
+	a.Equal([]string{"0", "1", "2"}, got[2])
+
+	chunks, _ := b.Subscribe()
+	_, ok := <-chunks
+	a.False(ok)
+}
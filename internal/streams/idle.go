@@ -0,0 +1,70 @@
+package streams
+
+import (
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+// idleTimeoutReader is a reader that is closed when a read waits for
+// longer than its timeout.
+type idleTimeoutReader struct {
+	rc      io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	// timedOut is set by the timer once it closed the reader.
+	timedOut atomic.Bool
+	// expired is set by Read once the timer fired during a read.
+	expired bool
+}
+
+// NewIdleTimeoutReader wraps a reader so that reads fail with
+// groqerr.ErrStreamIdleTimeout when no bytes arrive for the given timeout.
+//
+// Only the time spent waiting in Read counts toward the timeout, so slow
+// consumers do not time out. The underlying reader is closed on timeout to
+// unblock the pending read.
+func NewIdleTimeoutReader(
+	rc io.ReadCloser,
+	timeout time.Duration,
+) io.ReadCloser {
+	r := &idleTimeoutReader{rc: rc, timeout: timeout}
+	r.timer = time.AfterFunc(math.MaxInt64, func() {
+		r.timedOut.Store(true)
+		_ = rc.Close()
+	})
+	r.timer.Stop()
+	return r
+}
+
+// Read implements the io.Reader interface.
+//
+// Bytes read as the timeout elapses are returned, the timeout being
+// reported by the next read.
+func (r *idleTimeoutReader) Read(p []byte) (int, error) {
+	if r.expired {
+		return 0, groqerr.ErrStreamIdleTimeout{IdleTimeout: r.timeout}
+	}
+	r.timer.Reset(r.timeout)
+	n, err := r.rc.Read(p)
+	r.expired = !r.timer.Stop()
+	switch {
+	case r.expired && n > 0:
+		return n, nil
+	case r.expired:
+		return 0, groqerr.ErrStreamIdleTimeout{IdleTimeout: r.timeout}
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (r *idleTimeoutReader) Close() error {
+	r.timer.Stop()
+	if r.timedOut.Load() {
+		return nil
+	}
+	return r.rc.Close()
+}
@@ -0,0 +1,64 @@
+package streams_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdleTimeoutReader(t *testing.T) {
+	a := assert.New(t)
+	pr, pw := io.Pipe()
+	r := streams.NewIdleTimeoutReader(pr, 50*time.Millisecond)
+	go func() {
+		_, _ = pw.Write([]byte("data: {}\n\n"))
+		// a slow consumer does not time out
+		time.Sleep(100 * time.Millisecond)
+		_, _ = pw.Write([]byte("data: {}\n\n"))
+	}()
+	buf := make([]byte, 64)
+	n, err := r.Read(buf)
+	a.NoError(err)
+	a.Equal("data: {}\n\n", string(buf[:n]))
+	time.Sleep(100 * time.Millisecond)
+	_, err = r.Read(buf)
+	a.NoError(err)
+
+	_, err = r.Read(buf)
+	var idleErr groqerr.ErrStreamIdleTimeout
+	a.ErrorAs(err, &idleErr)
+	a.Equal(50*time.Millisecond, idleErr.IdleTimeout)
+	a.True(idleErr.Timeout())
+	_, err = r.Read(buf)
+	a.ErrorAs(err, &idleErr)
+	a.NoError(r.Close())
+
+	// bytes arriving as the timeout elapses come before the timeout
+	r = streams.NewIdleTimeoutReader(&lateReader{
+		delay: 100 * time.Millisecond,
+		data:  "data: {}\n\n",
+	}, 50*time.Millisecond)
+	n, err = r.Read(buf)
+	a.NoError(err)
+	a.Equal("data: {}\n\n", string(buf[:n]))
+	n, err = r.Read(buf)
+	a.Zero(n)
+	a.ErrorAs(err, &idleErr)
+}
+
+// lateReader returns its data after a delay, ignoring Close.
+type lateReader struct {
+	delay time.Duration
+	data  string
+}
+
+func (r *lateReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	return copy(p, r.data), nil
+}
+
+func (r *lateReader) Close() error { return nil }
@@ -0,0 +1,152 @@
+package streams
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+type (
+	// Event is a server-sent event.
+	//
+	// https://html.spec.whatwg.org/multipage/server-sent-events.html
+	Event struct {
+		// Type is the type of the event set by its event field.
+		//
+		// It is empty for the default "message" type.
+		Type string
+		// ID is the last event ID of the stream when the event was
+		// dispatched.
+		ID string
+		// Data is the data of the event, the values of its data fields
+		// joined by newlines.
+		Data []byte
+		// Retry is the reconnection time set by the retry field of the
+		// event, if any.
+		Retry time.Duration
+		// Comments are the comment lines of the event, without their
+		// leading colon.
+		Comments [][]byte
+	}
+	// EventDecoder decodes server-sent events from a reader.
+	EventDecoder struct {
+		r      *bufio.Reader
+		lastID string
+		// skipLF is set when the last line ended with a carriage return
+		// that may be followed by a line feed.
+		skipLF bool
+		// emptyLimit is the number of consecutive empty blocks skipped
+		// before failing, negative for no limit.
+		emptyLimit int
+	}
+)
+
+// NewEventDecoder creates a new server-sent event decoder reading from r.
+func NewEventDecoder(r io.Reader) *EventDecoder {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &EventDecoder{r: br, emptyLimit: -1}
+}
+
+// Next decodes the next event of the stream.
+//
+// An event is dispatched on a blank line only when it holds data, so that
+// blocks without data, such as comments sent as heartbeats, are skipped.
+// Fields unknown to the specification are ignored. A pending event is
+// dispatched when the stream ends without a final blank line. io.EOF is
+// returned once the stream is exhausted.
+func (d *EventDecoder) Next() (Event, error) {
+	var (
+		event Event
+		empty int
+	)
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			if err == io.EOF && len(event.Data) > 0 {
+				return d.dispatch(event), nil
+			}
+			return Event{}, err
+		}
+		if len(line) == 0 {
+			if len(event.Data) > 0 {
+				return d.dispatch(event), nil
+			}
+			if len(event.Comments) == 0 {
+				empty++
+				if d.emptyLimit >= 0 && empty > d.emptyLimit {
+					return Event{}, groqerr.ErrTooManyEmptyStreamMessages{}
+				}
+			}
+			event = Event{}
+			continue
+		}
+		if line[0] == ':' {
+			event.Comments = append(event.Comments, line[1:])
+			continue
+		}
+		field, value, found := bytes.Cut(line, []byte(":"))
+		if found {
+			value = bytes.TrimPrefix(value, []byte(" "))
+		}
+		switch string(field) {
+		case "event":
+			event.Type = string(value)
+		case "data":
+			event.Data = append(event.Data, value...)
+			event.Data = append(event.Data, '\n')
+		case "id":
+			if !bytes.ContainsRune(value, 0) {
+				d.lastID = string(value)
+			}
+		case "retry":
+			ms, err := strconv.ParseUint(string(value), 10, 63)
+			if err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// dispatch finalizes an event before it is returned.
+func (d *EventDecoder) dispatch(event Event) Event {
+	event.ID = d.lastID
+	event.Data = bytes.TrimSuffix(event.Data, []byte("\n"))
+	return event
+}
+
+// readLine reads a line ending with a line feed, a carriage return or both.
+//
+// The returned line does not hold its ending.
+func (d *EventDecoder) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				return line, nil
+			}
+			return nil, err
+		}
+		if d.skipLF {
+			d.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+		switch b {
+		case '\n':
+			return line, nil
+		case '\r':
+			d.skipLF = true
+			return line, nil
+		}
+		line = append(line, b)
+	}
+}
@@ -0,0 +1,83 @@
+package streams_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventDecoder(t *testing.T) {
+	a := assert.New(t)
+	d := streams.NewEventDecoder(strings.NewReader(
+		": keep-alive\n\n" +
+			"event: update\r\nid: 1\r\ndata: first\r\ndata:second\r\n\r\n" +
+			"retry: 1500\rdata:  spaced\r\r" +
+			"event: ping\n\n" +
+			"{\"error\": 1}\n\n" +
+			"data: unterminated",
+	))
+	// the heartbeat holds no data and is skipped
+	event, err := d.Next()
+	a.NoError(err)
+	a.Equal("update", event.Type)
+	a.Equal("1", event.ID)
+	a.Equal("first\nsecond", string(event.Data))
+
+	event, err = d.Next()
+	a.NoError(err)
+	a.Equal(1500*time.Millisecond, event.Retry)
+	a.Equal(" spaced", string(event.Data))
+	a.Equal("1", event.ID)
+
+	// events without data and unknown fields are dropped
+	event, err = d.Next()
+	a.NoError(err)
+	a.Empty(event.Type)
+	a.Equal("unterminated", string(event.Data))
+
+	_, err = d.Next()
+	a.ErrorIs(err, io.EOF)
+}
+
+func TestStreamReaderEvents(t *testing.T) {
+	a := assert.New(t)
+	heartbeats := strings.Repeat(": ping\n\n", 10)
+	stream := streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		io.NopCloser(bytes.NewBufferString(
+			heartbeats+
+				"data: {\"id\":\n"+
+				"data: \"1\"}\n\n"+
+				heartbeats+
+				"event: error\n"+
+				"data: {\"message\":\"overloaded\",\"type\":\"server_error\"}\n\n",
+		)),
+		nil,
+		1,
+	)
+	response, err := stream.Recv()
+	a.NoError(err)
+	a.Equal("1", response.ID)
+	_, err = stream.Recv()
+	var apiErr *groqerr.APIError
+	a.ErrorAs(err, &apiErr)
+	a.Equal("overloaded", apiErr.Message)
+
+	stream = streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		io.NopCloser(bytes.NewBufferString(
+			"event: usage\ndata: {}\n\n",
+		)),
+		nil,
+		1,
+	)
+	event, err := stream.RecvEvent()
+	a.NoError(err)
+	a.Equal("usage", event.Type)
+	a.Equal("{}", string(event.Data))
+}
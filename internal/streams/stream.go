@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
+	"sync"
 
 	"github.com/conneroisu/groq-go/pkg/groqerr"
 )
@@ -28,6 +31,11 @@ type (
 		readCloser         io.ReadCloser
 		ErrAccumulator     ErrorAccumulator
 		Header             http.Header // Header is the header of the response.
+		decoder            *EventDecoder
+
+		mu        sync.Mutex
+		observers []func(T, error)
+		observed  bool
 	}
 	// ErrorAccumulator is an interface for a unit that accumulates errors.
 	ErrorAccumulator interface {
@@ -46,57 +54,131 @@ func (stream *StreamReader[T]) Recv() (response T, err error) {
 		err = io.EOF
 		return response, err
 	}
-	return stream.processLines()
+	response, err = stream.processEvents()
+	stream.notify(response, err)
+	return response, err
 }
 
-// processLines processes the lines of the current response in the stream.
-func (stream *StreamReader[T]) processLines() (T, error) {
-	var (
-		headerData         = []byte("data: ")
-		errorPrefix        = []byte(`data: {"error":`)
-		emptyMessagesCount uint
-		hasErrorPrefix     bool
-	)
-	for {
-		rawLine, err := stream.Reader.ReadBytes('\n')
-		if err != nil || hasErrorPrefix {
-			respErr := stream.UnmarshalError()
-			if respErr != nil {
-				return *new(T),
-					fmt.Errorf("error, %w", respErr.Error)
-			}
-			return *new(T), err
-		}
-		noSpaceLine := bytes.TrimSpace(rawLine)
-		if bytes.HasPrefix(noSpaceLine, errorPrefix) {
-			hasErrorPrefix = true
-		}
-		if !bytes.HasPrefix(noSpaceLine, headerData) || hasErrorPrefix {
-			if hasErrorPrefix {
-				noSpaceLine = bytes.TrimPrefix(noSpaceLine, headerData)
-			}
-			err := stream.ErrAccumulator.Write(noSpaceLine)
-			if err != nil {
-				return *new(T), err
-			}
-			emptyMessagesCount++
-			if emptyMessagesCount > stream.emptyMessagesLimit {
-				return *new(T), groqerr.ErrTooManyEmptyStreamMessages{}
-			}
-			continue
-		}
-		noPrefixLine := bytes.TrimPrefix(noSpaceLine, headerData)
-		if string(noPrefixLine) == "[DONE]" {
-			stream.isFinished = true
-			return *new(T), io.EOF
+// Observe registers fn to be called with each response received from the
+// stream, e.g. to record metrics of the stream.
+//
+// Once the stream ends, fn is called a last time with the error ending it:
+// io.EOF when the stream completes, the receive error when it fails, or
+// io.ErrClosedPipe when it is closed before its end.
+func (stream *StreamReader[T]) Observe(fn func(T, error)) {
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.observers = append(stream.observers, fn)
+}
+
+// notify calls the observers of the stream with a received response or the
+// error ending the stream.
+//
+// The observers are called without holding the lock, so that they can use
+// the stream, e.g. to register other observers.
+func (stream *StreamReader[T]) notify(response T, err error) {
+	stream.mu.Lock()
+	if stream.observed {
+		stream.mu.Unlock()
+		return
+	}
+	stream.observed = err != nil
+	observers := slices.Clone(stream.observers)
+	stream.mu.Unlock()
+	for _, fn := range observers {
+		fn(response, err)
+	}
+}
+
+// RecvEvent receives the next raw server-sent event of the stream.
+//
+// Unlike Recv, it does not decode the data of the event nor interpret
+// error events, so that callers can handle event kinds unknown to the
+// stream reader.
+func (stream *StreamReader[T]) RecvEvent() (Event, error) {
+	if stream.isFinished {
+		return Event{}, io.EOF
+	}
+	if stream.decoder == nil {
+		stream.decoder = NewEventDecoder(stream.Reader)
+		stream.decoder.emptyLimit = int(stream.emptyMessagesLimit)
+	}
+	return stream.decoder.Next()
+}
+
+// processEvents processes the next event of the stream into a response.
+func (stream *StreamReader[T]) processEvents() (T, error) {
+	errorPrefix := []byte(`{"error":`)
+	if stream.decoder == nil {
+		// errors may be sent as a plain JSON body instead of events
+		start, err := stream.Reader.Peek(1)
+		if err == nil && start[0] == '{' {
+			return *new(T), stream.bodyError()
 		}
-		var response T
-		unmarshalErr := json.Unmarshal(noPrefixLine, &response)
-		if unmarshalErr != nil {
-			return *new(T), unmarshalErr
+	}
+	event, err := stream.RecvEvent()
+	var idleErr groqerr.ErrStreamIdleTimeout
+	if errors.As(err, &idleErr) {
+		return *new(T), err
+	}
+	if err != nil {
+		respErr := stream.UnmarshalError()
+		if respErr != nil {
+			return *new(T),
+				fmt.Errorf("error, %w", respErr.Error)
 		}
-		return response, nil
+		return *new(T), err
+	}
+	if event.Type == "error" || bytes.HasPrefix(event.Data, errorPrefix) {
+		return *new(T), stream.eventError(event)
+	}
+	if string(event.Data) == "[DONE]" {
+		stream.isFinished = true
+		return *new(T), io.EOF
+	}
+	var response T
+	unmarshalErr := json.Unmarshal(event.Data, &response)
+	if unmarshalErr != nil {
+		return *new(T), unmarshalErr
+	}
+	return response, nil
+}
+
+// eventError returns the error carried by an error event.
+func (stream *StreamReader[T]) eventError(event Event) error {
+	err := stream.ErrAccumulator.Write(event.Data)
+	if err != nil {
+		return err
+	}
+	respErr := stream.UnmarshalError()
+	if respErr != nil && respErr.Error != nil {
+		return fmt.Errorf("error, %w", respErr.Error)
+	}
+	// error events may carry the error without its envelope
+	var apiErr groqerr.APIError
+	if json.Unmarshal(event.Data, &apiErr) == nil && apiErr.Message != "" {
+		return fmt.Errorf("error, %w", &apiErr)
+	}
+	return fmt.Errorf("error event: %s", event.Data)
+}
+
+// bodyError returns the error carried by a plain JSON body, ending the
+// stream.
+func (stream *StreamReader[T]) bodyError() error {
+	stream.isFinished = true
+	data, err := io.ReadAll(stream.Reader)
+	if err != nil {
+		return err
+	}
+	err = stream.ErrAccumulator.Write(bytes.TrimSpace(data))
+	if err != nil {
+		return err
+	}
+	respErr := stream.UnmarshalError()
+	if respErr != nil && respErr.Error != nil {
+		return fmt.Errorf("error, %w", respErr.Error)
 	}
+	return fmt.Errorf("unexpected stream body: %s", data)
 }
 
 // UnmarshalError unmarshals the error response.
@@ -114,6 +196,7 @@ func (stream *StreamReader[T]) UnmarshalError() (errResp *groqerr.ErrorResponse)
 
 // Close closes the stream.
 func (stream *StreamReader[T]) Close() error {
+	stream.notify(*new(T), io.ErrClosedPipe)
 	return stream.readCloser.Close()
 }
 
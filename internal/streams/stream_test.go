@@ -148,3 +148,69 @@ func TestErrorByteWriteErrors(t *testing.T) {
 		t.Fatalf("Did not return error when write failed: %v", err)
 	}
 }
+
+// TestStreamReaderObserve tests the observers of a stream see each chunk
+// and the end of the stream once.
+func TestStreamReaderObserve(t *testing.T) {
+	a := assert.New(t)
+	body := "data: {\"id\":\"1\"}\n\ndata: {\"id\":\"2\"}\n\ndata: [DONE]\n\n"
+	stream := streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		io.NopCloser(bytes.NewReader([]byte(body))),
+		nil,
+		3,
+	)
+	var (
+		ids  []string
+		errs []error
+	)
+	stream.Observe(func(chunk *groq.ChatCompletionStreamResponse, err error) {
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		ids = append(ids, chunk.ID)
+	})
+	for {
+		_, err := stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+	a.NoError(stream.Close())
+	a.Equal([]string{"1", "2"}, ids)
+	a.Equal([]error{io.EOF}, errs)
+
+	stream = streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		io.NopCloser(bytes.NewReader([]byte(body))),
+		nil,
+		3,
+	)
+	errs = nil
+	stream.Observe(func(_ *groq.ChatCompletionStreamResponse, err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	})
+	_, err := stream.Recv()
+	a.NoError(err)
+	a.NoError(stream.Close())
+	a.Equal([]error{io.ErrClosedPipe}, errs)
+
+	// observers can use the stream they observe
+	stream = streams.NewStreamReader[groq.ChatCompletionStreamResponse](
+		io.NopCloser(bytes.NewReader([]byte(body))),
+		nil,
+		3,
+	)
+	errs = nil
+	stream.Observe(func(_ *groq.ChatCompletionStreamResponse, err error) {
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		a.NoError(stream.Close())
+	})
+	_, err = stream.Recv()
+	a.NoError(err)
+	a.Equal([]error{io.ErrClosedPipe}, errs)
+}
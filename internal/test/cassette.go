@@ -0,0 +1,405 @@
+package test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+const (
+	// ModeReplay replays the interactions of a cassette without touching
+	// the network, failing the requests matching none of them.
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests through the transport of the recorder
+	// and records their interactions into a new cassette.
+	ModeRecord
+)
+
+// redacted replaces the redacted values of cassettes.
+const redacted = "[REDACTED]"
+
+// RedactedHeaders are the headers redacted from the requests and responses
+// of recorded cassettes.
+var RedactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Api-Key",
+	"Openai-Organization",
+}
+
+type (
+	// Mode is the mode of a Recorder.
+	Mode int
+	// Cassette is a recording of the HTTP interactions of a test.
+	Cassette struct {
+		// Interactions are the recorded interactions, in the order their
+		// requests were sent.
+		Interactions []*Interaction `json:"interactions"`
+	}
+	// Interaction is a recorded request and its response.
+	Interaction struct {
+		Request  RecordedRequest  `json:"request"`
+		Response RecordedResponse `json:"response"`
+	}
+	// RecordedRequest is a recorded request.
+	RecordedRequest struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header,omitempty"`
+		// Body is the normalized body of the request: JSON bodies with
+		// sorted keys, and multipart bodies as their fields with files
+		// replaced by their name and hash.
+		Body string `json:"body,omitempty"`
+	}
+	// RecordedResponse is a recorded response.
+	RecordedResponse struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       string      `json:"body,omitempty"`
+		// Encoding is "base64" for bodies that are not valid UTF-8.
+		Encoding string `json:"encoding,omitempty"`
+	}
+	// Recorder is an http.RoundTripper recording the interactions of a
+	// test into a cassette file, or replaying them from it.
+	//
+	// Requests are matched to recorded interactions by method, path and
+	// normalized body, each interaction being replayed once in order.
+	// Streamed responses, e.g. server-sent events, are recorded as they
+	// are read and replayed at once.
+	Recorder struct {
+		// Transport sends the requests in ModeRecord.
+		//
+		// It defaults to http.DefaultTransport.
+		Transport http.RoundTripper
+		// Redact, if set, further redacts the recorded interactions, e.g.
+		// to scrub secrets from bodies, after the RedactedHeaders are.
+		Redact func(*Interaction)
+
+		mode     Mode
+		path     string
+		mu       sync.Mutex
+		cassette Cassette
+		replayed []bool
+		bodies   []*recordingBody
+	}
+	// recordingBody records the body of a response as it is read.
+	recordingBody struct {
+		io.ReadCloser
+		r    *Recorder
+		i    *Interaction
+		buf  bytes.Buffer
+		once sync.Once
+	}
+)
+
+// NewRecorder returns a recorder of the cassette file at the path.
+//
+// In ModeReplay the cassette is loaded from the file, and an error
+// wrapping fs.ErrNotExist is returned if it was never recorded. In
+// ModeRecord the cassette is written to the file by Stop.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		Transport: http.DefaultTransport,
+		mode:      mode,
+		path:      path,
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading cassette: %w", err)
+	}
+	err = json.Unmarshal(data, &r.cassette)
+	if err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+	}
+	r.replayed = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// NewCassetteClient returns an http client recording the interactions of
+// the test into the cassette file at the path when running integration
+// tests, see IsIntegrationTest, and replaying them otherwise.
+//
+// The test is skipped if the cassette has not been recorded yet.
+func NewCassetteClient(t testing.TB, path string) *http.Client {
+	t.Helper()
+	mode := ModeReplay
+	if IsIntegrationTest() {
+		mode = ModeRecord
+	}
+	r, err := NewRecorder(path, mode)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("cassette %s is not recorded, run with UNIT=1", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err := r.Stop()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	return &http.Client{Transport: r}
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode { return r.mode }
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	normalized := normalizeBody(req.Header.Get("Content-Type"), body)
+	if r.mode == ModeReplay {
+		return r.replay(req, normalized)
+	}
+	sent := req.Clone(req.Context())
+	sent.Body = io.NopCloser(bytes.NewReader(body))
+	res, err := r.Transport.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	i := &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+			Body:   normalized,
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+		},
+	}
+	b := &recordingBody{ReadCloser: res.Body, r: r, i: i}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.bodies = append(r.bodies, b)
+	r.mu.Unlock()
+	res.Body = b
+	return res, nil
+}
+
+// Stop ends the recording, writing the cassette to its file in
+// ModeRecord.
+//
+// Interactions whose response body was not entirely read are recorded
+// with the part of the body that was.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	bodies := r.bodies
+	r.mu.Unlock()
+	for _, b := range bodies {
+		b.complete()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.cassette.Interactions {
+		redactHeader(i.Request.Header)
+		redactHeader(i.Response.Header)
+		if r.Redact != nil {
+			r.Redact(i)
+		}
+	}
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(r.path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// replay returns the response of the first interaction matching the
+// request that was not replayed yet.
+func (r *Recorder) replay(
+	req *http.Request,
+	body string,
+) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, i := range r.cassette.Interactions {
+		if r.replayed[n] || !i.matches(req, body) {
+			continue
+		}
+		r.replayed[n] = true
+		data := []byte(i.Response.Body)
+		if i.Response.Encoding == "base64" {
+			var err error
+			data, err = base64.StdEncoding.DecodeString(i.Response.Body)
+			if err != nil {
+				return nil, fmt.Errorf("decoding recorded body: %w", err)
+			}
+		}
+		return &http.Response{
+			Status: fmt.Sprintf(
+				"%d %s",
+				i.Response.StatusCode,
+				http.StatusText(i.Response.StatusCode),
+			),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(data)),
+			ContentLength: int64(len(data)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf(
+		"cassette %s has no interaction left for %s %s",
+		r.path,
+		req.Method,
+		req.URL.Path,
+	)
+}
+
+// matches reports whether the request matches the recorded one.
+func (i *Interaction) matches(req *http.Request, body string) bool {
+	if i.Request.Method != req.Method || i.Request.Body != body {
+		return false
+	}
+	u, err := url.Parse(i.Request.URL)
+	return err == nil && u.Path == req.URL.Path
+}
+
+// Read records the read part of the body, completing the interaction at
+// the end of the body.
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err != nil {
+		b.complete()
+	}
+	return n, err
+}
+
+// Close completes the interaction and closes the body.
+func (b *recordingBody) Close() error {
+	b.complete()
+	return b.ReadCloser.Close()
+}
+
+// complete records the read body in the interaction.
+func (b *recordingBody) complete() {
+	b.once.Do(func() {
+		b.r.mu.Lock()
+		defer b.r.mu.Unlock()
+		data := b.buf.Bytes()
+		if utf8.Valid(data) {
+			b.i.Response.Body = string(data)
+			return
+		}
+		b.i.Response.Body = base64.StdEncoding.EncodeToString(data)
+		b.i.Response.Encoding = "base64"
+	})
+}
+
+// readBody reads and closes the body of the request.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %w", err)
+	}
+	return body, req.Body.Close()
+}
+
+// normalizeBody returns the normalized form of a request body, so that
+// requests can be matched regardless of their key order or multipart
+// boundary.
+func normalizeBody(contentType string, body []byte) string {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "multipart/") {
+		normalized, err := normalizeMultipart(body, params["boundary"])
+		if err == nil {
+			return normalized
+		}
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if dec.Decode(&v) == nil && !dec.More() {
+		normalized, err := json.Marshal(v)
+		if err == nil {
+			return string(normalized)
+		}
+	}
+	return string(body)
+}
+
+// normalizeMultipart returns the fields of a multipart body as JSON, with
+// files replaced by their name and the hash of their content.
+func normalizeMultipart(body []byte, boundary string) (string, error) {
+	fields := map[string][]string{}
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		value := string(data)
+		if part.FileName() != "" {
+			sum := sha256.Sum256(data)
+			value = fmt.Sprintf(
+				"file %s sha256:%s",
+				part.FileName(),
+				hex.EncodeToString(sum[:]),
+			)
+		}
+		fields[part.FormName()] = append(fields[part.FormName()], value)
+	}
+	for _, values := range fields {
+		sort.Strings(values)
+	}
+	normalized, err := json.Marshal(fields)
+	return string(normalized), err
+}
+
+// redactHeader redacts the RedactedHeaders of a header.
+func redactHeader(h http.Header) {
+	for _, name := range RedactedHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+}
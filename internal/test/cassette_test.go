@@ -0,0 +1,156 @@
+package test_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// exercise performs a chat completion, a streamed chat completion and a
+// transcription with a client using the transport.
+func exercise(
+	t *testing.T,
+	baseURL string,
+	transport http.RoundTripper,
+) (chat, stream, audio string) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, err := groq.NewClient(
+		test.GetTestToken(),
+		groq.WithBaseURL(baseURL),
+		groq.WithClient(&http.Client{Transport: transport}),
+	)
+	a.NoError(err)
+	req := groq.ChatCompletionRequest{
+		Model: groq.ModelLlama3370BVersatile,
+		Messages: []groq.ChatCompletionMessage{
+			{Role: groq.RoleUser, Content: "Hello"},
+		},
+	}
+	response, err := client.ChatCompletion(ctx, req)
+	a.NoError(err)
+	if len(response.Choices) > 0 {
+		chat = response.Choices[0].Message.Content
+	}
+	s, err := client.ChatCompletionStream(ctx, req)
+	a.NoError(err)
+	if err == nil {
+		accumulated, err := s.Accumulate()
+		a.NoError(err)
+		if len(accumulated.Choices) > 0 {
+			stream = accumulated.Choices[0].Message.Content
+		}
+	}
+	transcription, err := client.Transcribe(ctx, groq.AudioRequest{
+		Model:    groq.ModelWhisperLargeV3,
+		FilePath: "hello.mp3",
+		Reader:   bytes.NewReader([]byte{0xff, 0xfb, 0x90, 0x00}),
+	})
+	a.NoError(err)
+	return chat, stream, transcription.Text
+}
+
+func TestRecorder(t *testing.T) {
+	a := assert.New(t)
+	server := test.NewTestServer()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Set-Cookie", "session=secret")
+			if r.Header.Get("Accept") == "text/event-stream" {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = io.WriteString(w, ""+
+					`data: {"id":"1","choices":[{"index":0,`+
+					`"delta":{"role":"assistant","content":"Hi"}}]}`+"\n\n"+
+					`data: {"id":"1","choices":[{"index":0,`+
+					`"delta":{"content":" there"},"finish_reason":"stop"}]}`+
+					"\n\ndata: [DONE]\n\n",
+				)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"id":"1","choices":[{"index":0,`+
+				`"message":{"role":"assistant","content":"Hello!"}}]}`)
+		},
+	)
+	server.RegisterHandler(
+		"/v1/audio/transcriptions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, `{"text": "hello"}`)
+		},
+	)
+	ts := server.GroqTestServer()
+	ts.Start()
+	baseURL := ts.URL + "/v1"
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	recorder, err := test.NewRecorder(path, test.ModeRecord)
+	a.NoError(err)
+	chat, stream, audio := exercise(t, baseURL, recorder)
+	a.NoError(recorder.Stop())
+	ts.Close()
+	a.Equal("Hello!", chat)
+	a.Equal("Hi there", stream)
+	a.Equal("hello", audio)
+
+	data, err := os.ReadFile(path)
+	a.NoError(err)
+	a.NotContains(string(data), test.GetTestToken())
+	a.NotContains(string(data), "session=secret")
+	a.Contains(string(data), "sha256:")
+
+	// the server is gone, the interactions are replayed from the cassette
+	replayer, err := test.NewRecorder(path, test.ModeReplay)
+	a.NoError(err)
+	chat, stream, audio = exercise(t, baseURL, replayer)
+	a.Equal("Hello!", chat)
+	a.Equal("Hi there", stream)
+	a.Equal("hello", audio)
+
+	// every interaction is replayed once
+	_, err = (&http.Client{Transport: replayer}).Get(baseURL + "/models")
+	a.ErrorContains(err, "no interaction left for GET /v1/models")
+
+	_, err = test.NewRecorder(
+		filepath.Join(t.TempDir(), "missing.json"),
+		test.ModeReplay,
+	)
+	a.ErrorIs(err, os.ErrNotExist)
+}
+
+func TestRecorderUnclosedBody(t *testing.T) {
+	a := assert.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "data: first\n\ndata: second\n\n")
+		},
+	))
+	defer ts.Close()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder, err := test.NewRecorder(path, test.ModeRecord)
+	a.NoError(err)
+	res, err := (&http.Client{Transport: recorder}).Get(ts.URL + "/v1/models")
+	a.NoError(err)
+	first := make([]byte, len("data: first\n\n"))
+	_, err = io.ReadFull(res.Body, first)
+	a.NoError(err)
+
+	// the part of the body read before stopping is recorded
+	a.NoError(recorder.Stop())
+	replayer, err := test.NewRecorder(path, test.ModeReplay)
+	a.NoError(err)
+	res, err = (&http.Client{Transport: replayer}).Get(ts.URL + "/v1/models")
+	a.NoError(err)
+	body, err := io.ReadAll(res.Body)
+	a.NoError(err)
+	a.Equal("data: first\n\n", string(body))
+}
@@ -0,0 +1,290 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// Limit is a client side rate limit for a model.
+	//
+	// A zero field leaves the corresponding dimension unlimited.
+	Limit struct {
+		// RequestsPerMinute is the number of requests allowed per
+		// minute.
+		RequestsPerMinute int
+		// TokensPerMinute is the number of tokens allowed per minute.
+		TokensPerMinute int
+	}
+	// RateLimiter is a token bucket rate limiter for chat completion
+	// requests.
+	//
+	// Callers are blocked until the model they target has capacity for the
+	// request. The tokens of a request, its estimated prompt along with its
+	// MaxTokens, are reserved before it is sent; the difference with the
+	// actual usage is given back once known, and all of them are given back
+	// when the request fails. The daily request limit and the per minute
+	// token limit reported by the rate limit headers of the API are
+	// enforced along with the configured limits.
+	//
+	// It is safe for concurrent use and can be shared across clients with
+	// WithRateLimiter.
+	RateLimiter struct {
+		mu      sync.Mutex
+		limits  map[ChatModel]Limit
+		buckets map[ChatModel]*modelBuckets
+		now     func() time.Time
+	}
+	// modelBuckets are the request and token buckets of a single model.
+	modelBuckets struct {
+		requests bucket
+		daily    bucket
+		tokens   bucket
+	}
+	// bucket is a token bucket refilled continuously over its period.
+	bucket struct {
+		capacity float64
+		level    float64
+		period   time.Duration
+		updated  time.Time
+	}
+)
+
+// NewRateLimiter creates a new rate limiter enforcing the given per model
+// limits.
+//
+// Models without a configured limit are only limited once the API reports
+// their token limit through the rate limit headers.
+func NewRateLimiter(limits map[ChatModel]Limit) *RateLimiter {
+	l := &RateLimiter{
+		limits:  make(map[ChatModel]Limit, len(limits)),
+		buckets: make(map[ChatModel]*modelBuckets),
+		now:     time.Now,
+	}
+	for model, limit := range limits {
+		l.limits[model] = limit
+	}
+	return l
+}
+
+// Wait blocks until the model has capacity for one request of the given
+// number of tokens, and reserves that capacity.
+//
+// It returns early with an error if the context is done or if its deadline
+// would pass before capacity is available.
+func (l *RateLimiter) Wait(
+	ctx context.Context,
+	model ChatModel,
+	tokens int,
+) error {
+	for {
+		delay := l.reserve(model, tokens)
+		if delay == 0 {
+			return nil
+		}
+		deadline, ok := ctx.Deadline()
+		if ok && l.now().Add(delay).After(deadline) {
+			return fmt.Errorf(
+				"rate limit for model %s requires waiting %s: %w",
+				model,
+				delay,
+				context.DeadlineExceeded,
+			)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Observe corrects the limiter's state for a model from the rate limit
+// headers of a response and, when known (non-negative), the actual number
+// of tokens used by a request for which reserved tokens were waited for.
+//
+// A failed request used no tokens, giving back all of the reserved ones.
+func (l *RateLimiter) Observe(
+	model ChatModel,
+	rl RateLimit,
+	reserved, used int,
+) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b := l.bucketsFor(model, now)
+	adopted := b.tokens.capacity == 0 && rl.LimitTokens > 0
+	if adopted {
+		b.tokens = newBucket(rl.LimitTokens, time.Minute, now)
+	}
+	b.tokens.refill(now)
+	if used >= 0 && !adopted {
+		b.tokens.level = min(
+			b.tokens.capacity,
+			b.tokens.level-float64(used-reserved),
+		)
+	}
+	if rl.LimitTokens > 0 && rl.RemainingTokens != nil {
+		b.tokens.level = min(b.tokens.level, float64(*rl.RemainingTokens))
+	}
+	if b.daily.capacity == 0 && rl.LimitRequests > 0 {
+		b.daily = newBucket(rl.LimitRequests, 24*time.Hour, now)
+	}
+	if rl.LimitRequests > 0 && rl.RemainingRequests != nil {
+		b.daily.refill(now)
+		b.daily.level = min(
+			b.daily.level,
+			float64(*rl.RemainingRequests),
+		)
+	}
+}
+
+// reserve takes capacity for a request from the model's buckets, returning
+// zero on success or the delay after which to try again.
+func (l *RateLimiter) reserve(model ChatModel, tokens int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b := l.bucketsFor(model, now)
+	delay := max(
+		b.requests.delay(now, 1),
+		b.daily.delay(now, 1),
+		b.tokens.delay(now, float64(tokens)),
+	)
+	if delay > 0 {
+		return delay
+	}
+	b.requests.take(1)
+	b.daily.take(1)
+	b.tokens.take(float64(tokens))
+	return 0
+}
+
+// bucketsFor returns the buckets of a model, creating them if needed.
+//
+// It must be called with the lock held.
+func (l *RateLimiter) bucketsFor(model ChatModel, now time.Time) *modelBuckets {
+	b, ok := l.buckets[model]
+	if ok {
+		return b
+	}
+	limit := l.limits[model]
+	b = &modelBuckets{
+		requests: newBucket(limit.RequestsPerMinute, time.Minute, now),
+		tokens:   newBucket(limit.TokensPerMinute, time.Minute, now),
+	}
+	l.buckets[model] = b
+	return b
+}
+
+// newBucket creates a full bucket of the given capacity per period.
+func newBucket(capacity int, period time.Duration, now time.Time) bucket {
+	return bucket{
+		capacity: float64(capacity),
+		level:    float64(capacity),
+		period:   period,
+		updated:  now,
+	}
+}
+
+// refill adds the capacity regained since the last update.
+func (b *bucket) refill(now time.Time) {
+	if b.capacity == 0 {
+		return
+	}
+	elapsed := now.Sub(b.updated)
+	b.updated = now
+	if elapsed <= 0 {
+		return
+	}
+	b.level = min(
+		b.capacity,
+		b.level+b.capacity*float64(elapsed)/float64(b.period),
+	)
+}
+
+// delay refills the bucket and returns how long to wait until n can be
+// taken from it.
+//
+// Requests larger than the capacity only wait for a full bucket.
+func (b *bucket) delay(now time.Time, n float64) time.Duration {
+	if b.capacity == 0 {
+		return 0
+	}
+	b.refill(now)
+	n = min(n, b.capacity)
+	if b.level >= n {
+		return 0
+	}
+	missing := n - b.level
+	return time.Duration(missing / b.capacity * float64(b.period))
+}
+
+// take removes n from the bucket.
+func (b *bucket) take(n float64) {
+	if b.capacity == 0 {
+		return
+	}
+	b.level -= min(n, b.capacity)
+}
+
+// limit waits for the client's rate limiter, if any, to have capacity for
+// the request, reserving its estimated prompt tokens and its MaxTokens.
+//
+// The returned function must be called with the response header, the
+// usage of the request when known (non-zero), and the error of the request
+// to correct the limiter: a failed request gives back its reserved tokens.
+func (c *Client) limit(
+	ctx context.Context,
+	request ChatCompletionRequest,
+) (func(header http.Header, usage *Usage, err error), error) {
+	if c.limiter == nil {
+		return func(http.Header, *Usage, error) {}, nil
+	}
+	tokens := estimateTokens(request) + request.MaxTokens
+	err := c.limiter.Wait(ctx, request.Model, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return func(header http.Header, usage *Usage, err error) {
+		used := -1
+		switch {
+		case err != nil:
+			used = 0
+		case usage != nil && usage.TotalTokens > 0:
+			used = usage.TotalTokens
+		}
+		c.limiter.Observe(request.Model, newRateLimit(header), tokens, used)
+	}, nil
+}
+
+// estimateTokens estimates the number of prompt tokens of a chat completion
+// request using roughly four characters per token.
+func estimateTokens(request ChatCompletionRequest) int {
+	const (
+		charsPerToken     = 4
+		tokensPerMessage  = 4
+		tokensPerResponse = 3
+	)
+	chars := 0
+	tokens := tokensPerResponse
+	for _, m := range request.Messages {
+		tokens += tokensPerMessage
+		chars += len(m.Name) + len(m.Content)
+		for _, part := range m.MultiContent {
+			chars += len(part.Text)
+		}
+		for _, call := range m.ToolCalls {
+			chars += len(call.Function.Name) + len(call.Function.Arguments)
+		}
+	}
+	if len(request.Tools) > 0 {
+		b, err := json.Marshal(request.Tools)
+		if err == nil {
+			chars += len(b)
+		}
+	}
+	return tokens + (chars+charsPerToken-1)/charsPerToken
+}
@@ -0,0 +1,163 @@
+package groq
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	a := assert.New(t)
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(map[ChatModel]Limit{
+		ModelLlama38B8192:  {RequestsPerMinute: 2},
+		ModelLlama370B8192: {TokensPerMinute: 600},
+	})
+	l.now = func() time.Time { return now }
+
+	a.Zero(l.reserve(ModelLlama38B8192, 100))
+	a.Zero(l.reserve(ModelLlama38B8192, 100))
+	// requests bucket is empty, one request is regained every 30s
+	a.Equal(30*time.Second, l.reserve(ModelLlama38B8192, 100))
+	now = now.Add(30 * time.Second)
+	a.Zero(l.reserve(ModelLlama38B8192, 100))
+
+	a.Zero(l.reserve(ModelLlama370B8192, 500))
+	// tokens bucket holds 100 tokens, ten are regained every second
+	a.Equal(10*time.Second, l.reserve(ModelLlama370B8192, 200))
+	// requests larger than the capacity wait for a full bucket
+	a.Equal(50*time.Second, l.reserve(ModelLlama370B8192, 10_000))
+	now = now.Add(50 * time.Second)
+	a.Zero(l.reserve(ModelLlama370B8192, 10_000))
+
+	// other models are not limited
+	a.Zero(l.reserve(ModelGemma29BIt, 1_000_000))
+}
+
+func TestRateLimiterObserve(t *testing.T) {
+	a := assert.New(t)
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(nil)
+	l.now = func() time.Time { return now }
+
+	a.Zero(l.reserve(ModelLlama38B8192, 1000))
+	remaining := 0
+	l.Observe(ModelLlama38B8192, RateLimit{
+		LimitTokens:     6000,
+		RemainingTokens: &remaining,
+	}, 1000, 1200)
+	// the tokens limit is adopted from the headers
+	a.Equal(time.Second, l.reserve(ModelLlama38B8192, 100))
+
+	l.Observe(ModelGemma29BIt, RateLimit{}, 10, 10)
+	a.Zero(l.reserve(ModelGemma29BIt, 1_000_000))
+
+	// unused reserved tokens are given back
+	l = NewRateLimiter(map[ChatModel]Limit{
+		ModelLlama38B8192: {TokensPerMinute: 600},
+	})
+	l.now = func() time.Time { return now }
+	a.Zero(l.reserve(ModelLlama38B8192, 500))
+	l.Observe(ModelLlama38B8192, RateLimit{}, 500, 100)
+	a.Zero(l.reserve(ModelLlama38B8192, 500))
+	// as are all of them when the request failed
+	l.Observe(ModelLlama38B8192, RateLimit{}, 500, 0)
+	a.Zero(l.reserve(ModelLlama38B8192, 500))
+	a.Equal(50*time.Second, l.reserve(ModelLlama38B8192, 500))
+}
+
+func TestRateLimiterDaily(t *testing.T) {
+	a := assert.New(t)
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(map[ChatModel]Limit{
+		ModelLlama38B8192: {RequestsPerMinute: 30},
+	})
+	l.now = func() time.Time { return now }
+	a.Zero(l.reserve(ModelLlama38B8192, 0))
+	remaining := 1
+	l.Observe(ModelLlama38B8192, RateLimit{
+		LimitRequests:     14_400,
+		RemainingRequests: &remaining,
+	}, 0, -1)
+	// the daily requests do not limit the per minute ones
+	a.Zero(l.reserve(ModelLlama38B8192, 0))
+	// one daily request is regained every six seconds
+	a.Equal(6*time.Second, l.reserve(ModelLlama38B8192, 0))
+	now = now.Add(6 * time.Second)
+	a.Zero(l.reserve(ModelLlama38B8192, 0))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	a := assert.New(t)
+	l := NewRateLimiter(map[ChatModel]Limit{
+		ModelLlama38B8192: {RequestsPerMinute: 6000},
+	})
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.NoError(l.Wait(context.Background(), ModelLlama38B8192, 10))
+		}()
+	}
+	wg.Wait()
+
+	l = NewRateLimiter(map[ChatModel]Limit{
+		ModelLlama38B8192: {RequestsPerMinute: 1},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	a.NoError(l.Wait(ctx, ModelLlama38B8192, 10))
+	a.ErrorIs(l.Wait(ctx, ModelLlama38B8192, 10), context.DeadlineExceeded)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	a := assert.New(t)
+	short := estimateTokens(ChatCompletionRequest{
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Hello!"},
+		},
+	})
+	long := estimateTokens(ChatCompletionRequest{
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Hello! How are you doing today?"},
+		},
+	})
+	a.Positive(short)
+	a.Greater(long, short)
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", handleModerationEndpoint)
+	WithRateLimiter(NewRateLimiter(map[ChatModel]Limit{
+		ModelLlama38B8192: {RequestsPerMinute: 1},
+	}))(client)
+	req := ChatCompletionRequest{
+		Model: ModelLlama38B8192,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Hello!"},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := client.ChatCompletion(ctx, req)
+	a.NoError(err)
+	_, err = client.ChatCompletion(ctx, req)
+	a.ErrorIs(err, context.DeadlineExceeded)
+
+	// the completion tokens are reserved along with the prompt ones
+	WithRateLimiter(NewRateLimiter(map[ChatModel]Limit{
+		ModelLlama38B8192: {TokensPerMinute: 1000},
+	}))(client)
+	req.MaxTokens = 2000
+	_, err = client.ChatCompletion(ctx, req)
+	a.NoError(err)
+	_, err = client.ChatCompletion(ctx, req)
+	a.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,263 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces the redacted values of logs.
+const redacted = "[REDACTED]"
+
+type (
+	// LogPolicy configures what the logs of a Groq client reveal.
+	//
+	// The zero value redacts the contents of messages. The API key is
+	// never logged.
+	LogPolicy struct {
+		// Contents includes the contents of messages, tool call arguments,
+		// embedding inputs and transcripts in the logs.
+		Contents bool
+	}
+	// logged is the request or response of a call, logged according to a
+	// log policy.
+	logged struct {
+		v      any
+		policy LogPolicy
+	}
+)
+
+// WithLogPolicy sets what the logs of the Groq client reveal.
+func WithLogPolicy(policy LogPolicy) Opts {
+	return func(c *Client) { c.logPolicy = policy }
+}
+
+// logCalls returns a handler logging the start, outcome and token usage of
+// the calls performed by next.
+func (c *Client) logCalls(next Handler) Handler {
+	return func(ctx context.Context, call *Call) (any, error) {
+		logger := c.logger.With(
+			"operation", call.Operation,
+			"model", call.Model,
+		)
+		logger.DebugContext(
+			ctx,
+			"groq call started",
+			"request", logged{call.Request, c.logPolicy},
+		)
+		start := time.Now()
+		res, err := next(ctx, call)
+		if err != nil {
+			logger.WarnContext(
+				ctx,
+				"groq call failed",
+				"duration", time.Since(start),
+				"error", err,
+			)
+			return res, err
+		}
+		if stream, ok := res.(*ChatCompletionStream); ok {
+			logStream(ctx, logger, start, stream)
+			return res, nil
+		}
+		logger.InfoContext(
+			ctx,
+			"groq call completed",
+			"duration", time.Since(start),
+			"response", logged{res, c.logPolicy},
+		)
+		return res, nil
+	}
+}
+
+// logStream logs the lifecycle of a streamed chat completion: its first
+// chunk, its token usage and how it ended.
+func logStream(
+	ctx context.Context,
+	logger *slog.Logger,
+	start time.Time,
+	stream *ChatCompletionStream,
+) {
+	logger.DebugContext(
+		ctx,
+		"groq stream opened",
+		"duration", time.Since(start),
+	)
+	var (
+		mu     sync.Mutex
+		chunks int
+		usage  *Usage
+	)
+	stream.Observe(func(chunk *ChatCompletionStreamResponse, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err == nil {
+			chunks++
+			if chunks == 1 {
+				logger.DebugContext(
+					ctx,
+					"groq stream first chunk",
+					"duration", time.Since(start),
+				)
+			}
+			if chunk.Usage != nil {
+				usage = chunk.Usage
+			}
+			if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+				usage = chunk.XGroq.Usage
+			}
+			return
+		}
+		args := []any{"duration", time.Since(start), "chunks", chunks}
+		if usage != nil {
+			args = append(args, usageAttr(*usage))
+		}
+		switch {
+		case errors.Is(err, io.EOF):
+			logger.InfoContext(ctx, "groq stream completed", args...)
+		case errors.Is(err, io.ErrClosedPipe):
+			logger.InfoContext(ctx, "groq stream closed", args...)
+		default:
+			args = append(args, "error", err)
+			logger.WarnContext(ctx, "groq stream failed", args...)
+		}
+	})
+}
+
+// content returns the content as revealed by the policy.
+func (p LogPolicy) content(s string) string {
+	if p.Contents || s == "" {
+		return s
+	}
+	return redacted
+}
+
+// LogValue implements the slog.LogValuer interface.
+func (l logged) LogValue() slog.Value {
+	p := l.policy
+	switch v := l.v.(type) {
+	case *ChatCompletionRequest:
+		attrs := []slog.Attr{p.messages(v.Messages)}
+		if v.MaxTokens > 0 {
+			attrs = append(attrs, slog.Int("max_tokens", v.MaxTokens))
+		}
+		if len(v.Tools) > 0 {
+			attrs = append(attrs, slog.Int("tools", len(v.Tools)))
+		}
+		return slog.GroupValue(attrs...)
+	case *ModerationRequest:
+		return slog.GroupValue(p.messages(v.Messages))
+	case *EmbeddingRequest:
+		inputs := make([]slog.Attr, len(v.Input))
+		for i, input := range v.Input {
+			inputs[i] = slog.String(strconv.Itoa(i), p.content(input))
+		}
+		return slog.GroupValue(slog.Attr{
+			Key:   "input",
+			Value: slog.GroupValue(inputs...),
+		})
+	case *AudioRequest:
+		return slog.GroupValue(
+			slog.String("file", v.FilePath),
+			slog.String("prompt", p.content(v.Prompt)),
+		)
+	case *Model:
+		return slog.StringValue(string(*v))
+	case ChatCompletionResponse:
+		choices := make([]slog.Attr, len(v.Choices))
+		for i, choice := range v.Choices {
+			choices[i] = slog.Group(
+				strconv.Itoa(choice.Index),
+				slog.String("finish_reason", string(choice.FinishReason)),
+				p.message("message", choice.Message),
+			)
+		}
+		return slog.GroupValue(
+			slog.String("id", v.ID),
+			slog.Attr{Key: "choices", Value: slog.GroupValue(choices...)},
+			usageAttr(v.Usage),
+		)
+	case EmbeddingResponse:
+		return slog.GroupValue(
+			slog.Int("embeddings", len(v.Data)),
+			usageAttr(v.Usage),
+		)
+	case ModerationResponse:
+		categories := make([]string, len(v.Categories))
+		for i, category := range v.Categories {
+			categories[i] = string(category)
+		}
+		return slog.GroupValue(
+			slog.String("categories", strings.Join(categories, ",")),
+			usageAttr(v.Usage),
+		)
+	case AudioResponse:
+		return slog.GroupValue(
+			slog.Float64("duration", v.Duration),
+			slog.String("text", p.content(v.Text)),
+		)
+	case ModelList:
+		return slog.GroupValue(slog.Int("models", len(v.Data)))
+	case ModelMetadata:
+		return slog.GroupValue(slog.String("id", string(v.ID)))
+	default:
+		return slog.GroupValue()
+	}
+}
+
+// messages returns the attribute of the messages of a request.
+func (p LogPolicy) messages(messages []ChatCompletionMessage) slog.Attr {
+	attrs := make([]slog.Attr, len(messages))
+	for i, message := range messages {
+		attrs[i] = p.message(strconv.Itoa(i), message)
+	}
+	return slog.Attr{Key: "messages", Value: slog.GroupValue(attrs...)}
+}
+
+// message returns the attribute of a message.
+func (p LogPolicy) message(
+	key string,
+	message ChatCompletionMessage,
+) slog.Attr {
+	content := message.Content
+	for _, part := range message.MultiContent {
+		content += part.Text
+	}
+	attrs := []slog.Attr{
+		slog.String("role", string(message.Role)),
+		slog.String("content", p.content(content)),
+	}
+	if len(message.ToolCalls) > 0 {
+		calls := make([]slog.Attr, len(message.ToolCalls))
+		for i, call := range message.ToolCalls {
+			calls[i] = slog.Group(
+				strconv.Itoa(i),
+				slog.String("name", call.Function.Name),
+				slog.String("arguments", p.content(call.Function.Arguments)),
+			)
+		}
+		attrs = append(attrs, slog.Attr{
+			Key:   "tool_calls",
+			Value: slog.GroupValue(calls...),
+		})
+	}
+	if message.ToolCallID != "" {
+		attrs = append(attrs, slog.String("tool_call_id", message.ToolCallID))
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// usageAttr returns the attribute of the token usage of a response.
+func usageAttr(usage Usage) slog.Attr {
+	return slog.Group(
+		"usage",
+		slog.Int("prompt_tokens", usage.PromptTokens),
+		slog.Int("completion_tokens", usage.CompletionTokens),
+		slog.Int("total_tokens", usage.TotalTokens),
+	)
+}
@@ -0,0 +1,139 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go/internal/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// logRecords decodes the records logged by a JSON handler keyed by message.
+func logRecords(t *testing.T, buf *bytes.Buffer) map[string]map[string]any {
+	records := map[string]map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		err := json.Unmarshal([]byte(line), &record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records[record["msg"].(string)] = record
+	}
+	return records
+}
+
+func TestLogging(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	server := test.NewTestServer()
+	ts := server.GroqTestServer()
+	ts.Start()
+	defer ts.Close()
+	var calls atomic.Int32
+	echo := handleEchoEndpoint(t)
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			echo(w, r)
+		},
+	)
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "my password is hunter2"},
+		},
+	}
+	newClient := func(buf *bytes.Buffer, opts ...Opts) *Client {
+		client, err := NewClient(test.GetTestToken(), append([]Opts{
+			WithBaseURL(ts.URL + "/v1"),
+			WithRetryPolicy(RetryPolicy{InitialBackoff: time.Millisecond}),
+			WithLogger(slog.New(slog.NewJSONHandler(
+				buf,
+				&slog.HandlerOptions{Level: slog.LevelDebug},
+			))),
+		}, opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+
+	var buf bytes.Buffer
+	_, err := newClient(&buf).ChatCompletion(ctx, req)
+	a.NoError(err)
+	a.NotContains(buf.String(), "hunter2")
+	a.NotContains(buf.String(), test.GetTestToken())
+	records := logRecords(t, &buf)
+	a.Equal(map[string]any{
+		"messages": map[string]any{
+			"0": map[string]any{"role": "user", "content": redacted},
+		},
+	}, records["groq call started"]["request"])
+	a.NotContains(records["sending groq request"], "api_key")
+	a.Equal(float64(1), records["retrying groq request"]["attempt"])
+	a.Equal(
+		float64(http.StatusServiceUnavailable),
+		records["retrying groq request"]["status"],
+	)
+	a.Equal(float64(http.StatusOK), records["received groq response"]["status"])
+	completed := records["groq call completed"]
+	a.Equal("chat.completion", completed["operation"])
+	a.Equal(string(ModelLlama3370BVersatile), completed["model"])
+	a.Contains(completed["response"], "usage")
+
+	buf.Reset()
+	calls.Store(1)
+	_, err = newClient(
+		&buf,
+		WithLogPolicy(LogPolicy{Contents: true}),
+	).ChatCompletion(ctx, req)
+	a.NoError(err)
+	a.Contains(buf.String(), "hunter2")
+	a.NotContains(buf.String(), test.GetTestToken())
+}
+
+func TestLoggingStream(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		handleStreamEndpoint(t, toolCallChunks...),
+	)
+	var buf bytes.Buffer
+	WithLogger(slog.New(slog.NewJSONHandler(
+		&buf,
+		&slog.HandlerOptions{Level: slog.LevelDebug},
+	)))(client)
+	stream, err := client.ChatCompletionStream(
+		context.Background(),
+		ChatCompletionRequest{
+			Model: ModelLlama3370BVersatile,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "What is the weather in Paris?"},
+			},
+			Stream: true,
+		},
+	)
+	a.NoError(err)
+	_, err = stream.Accumulate()
+	a.NoError(err)
+	records := logRecords(t, &buf)
+	a.Contains(records, "groq stream opened")
+	a.Contains(records, "groq stream first chunk")
+	completed := records["groq stream completed"]
+	a.Equal(float64(len(toolCallChunks)), completed["chunks"])
+	a.Contains(completed, "usage")
+	a.NotContains(records, "groq call completed")
+}
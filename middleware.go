@@ -0,0 +1,162 @@
+package groq
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+const (
+	// OperationChatCompletion is the operation of ChatCompletion calls.
+	//
+	// Its request is a *ChatCompletionRequest and its response a
+	// ChatCompletionResponse.
+	OperationChatCompletion Operation = "chat.completion"
+	// OperationChatCompletionStream is the operation of
+	// ChatCompletionStream calls.
+	//
+	// Its request is a *ChatCompletionRequest and its response a
+	// *ChatCompletionStream, whose chunks can be observed through
+	// ChatCompletionStream.Observe.
+	OperationChatCompletionStream Operation = "chat.completion.stream"
+	// OperationModeration is the operation of Moderate and
+	// CreateModeration calls.
+	//
+	// Its request is a *ModerationRequest and its response a
+	// ModerationResponse.
+	OperationModeration Operation = "moderation"
+	// OperationEmbedding is the operation of Embed calls.
+	//
+	// Its request is an *EmbeddingRequest and its response an
+	// EmbeddingResponse.
+	OperationEmbedding Operation = "embedding"
+	// OperationTranscription is the operation of Transcribe calls.
+	//
+	// Its request is an *AudioRequest and its response an AudioResponse.
+	OperationTranscription Operation = "audio.transcription"
+	// OperationTranslation is the operation of Translate calls.
+	//
+	// Its request is an *AudioRequest and its response an AudioResponse.
+	OperationTranslation Operation = "audio.translation"
+	// OperationListModels is the operation of ListModels calls.
+	//
+	// Its request is nil and its response a ModelList.
+	OperationListModels Operation = "models.list"
+	// OperationGetModel is the operation of GetModel calls.
+	//
+	// Its request is a *Model and its response a ModelMetadata.
+	OperationGetModel Operation = "models.get"
+)
+
+type (
+	// Operation is the kind of a call to the Groq API.
+	//
+	// string
+	Operation string
+	// Call is a logical call to the Groq API as seen by middlewares.
+	Call struct {
+		// Operation is the kind of the call, which determines the types
+		// of its request and response.
+		Operation Operation
+		// Model is the model of the call, if any.
+		Model string
+		// Request points to the logical request of the call.
+		//
+		// Middlewares may modify the request it points to before calling
+		// the next handler, e.g. to redact messages.
+		Request any
+	}
+	// Handler performs a call, returning its decoded response.
+	Handler func(ctx context.Context, call *Call) (any, error)
+	// Middleware wraps the handler of the calls of a client, e.g. to log,
+	// cache or meter them.
+	//
+	// A middleware may return a response without calling next, in which
+	// case the response must be of the type of the operation of the call.
+	Middleware func(next Handler) Handler
+)
+
+// responseTypes are the types of the responses of the operations.
+var responseTypes = map[Operation]reflect.Type{
+	OperationChatCompletion:       reflect.TypeFor[ChatCompletionResponse](),
+	OperationChatCompletionStream: reflect.TypeFor[*ChatCompletionStream](),
+	OperationModeration:           reflect.TypeFor[ModerationResponse](),
+	OperationEmbedding:            reflect.TypeFor[EmbeddingResponse](),
+	OperationTranscription:        reflect.TypeFor[AudioResponse](),
+	OperationTranslation:          reflect.TypeFor[AudioResponse](),
+	OperationListModels:           reflect.TypeFor[ModelList](),
+	OperationGetModel:             reflect.TypeFor[ModelMetadata](),
+}
+
+// WithMiddleware adds middlewares to the Groq client.
+//
+// Middlewares apply to every call of the client, including streamed chat
+// completions. The first middleware is the outermost one.
+func WithMiddleware(middlewares ...Middleware) Opts {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// Intercept returns a middleware intercepting the calls whose request and
+// response are of the types Req and Resp, e.g. ChatCompletionRequest and
+// ChatCompletionResponse, and passing other calls through.
+//
+//	groq.Intercept(func(
+//		ctx context.Context,
+//		req *groq.ChatCompletionRequest,
+//		next func(context.Context) (groq.ChatCompletionResponse, error),
+//	) (groq.ChatCompletionResponse, error) {
+//		req.User = "anonymous"
+//		return next(ctx)
+//	})
+func Intercept[Req, Resp any](
+	fn func(
+		ctx context.Context,
+		req *Req,
+		next func(context.Context) (Resp, error),
+	) (Resp, error),
+) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, error) {
+			req, ok := call.Request.(*Req)
+			want := responseTypes[call.Operation]
+			if !ok || want != reflect.TypeFor[Resp]() {
+				return next(ctx, call)
+			}
+			return fn(ctx, req, func(ctx context.Context) (Resp, error) {
+				res, err := next(ctx, call)
+				resp, _ := res.(Resp)
+				return resp, err
+			})
+		}
+	}
+}
+
+// invoke performs a call through the middlewares of the client, handling
+// it with h once they call the innermost handler.
+func invoke[Resp any](
+	ctx context.Context,
+	c *Client,
+	call Call,
+	h func(ctx context.Context) (Resp, error),
+) (Resp, error) {
+	handler := c.logCalls(func(ctx context.Context, _ *Call) (any, error) {
+		return h(ctx)
+	})
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	res, err := handler(ctx, &call)
+	resp, ok := res.(Resp)
+	if !ok && res != nil {
+		var zero Resp
+		return zero, fmt.Errorf(
+			"middleware returned a %T response for %s, want %T",
+			res,
+			call.Operation,
+			zero,
+		)
+	}
+	return resp, err
+}
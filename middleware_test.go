@@ -0,0 +1,178 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// handleEchoEndpoint answers chat completions with the content of the last
+// message of the request.
+func handleEchoEndpoint(
+	t *testing.T,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(ChatCompletionResponse{
+			ID: "chatcmpl-echo",
+			Choices: []ChatCompletionChoice{{
+				Message: ChatCompletionMessage{
+					Role:    RoleAssistant,
+					Content: req.Messages[len(req.Messages)-1].Content,
+				},
+				FinishReason: ReasonStop,
+			}},
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler("/v1/chat/completions", handleEchoEndpoint(t))
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) (any, error) {
+				calls = append(calls, name+" "+string(call.Operation))
+				return next(ctx, call)
+			}
+		}
+	}
+	redact := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) (any, error) {
+			if req, ok := call.Request.(*ChatCompletionRequest); ok {
+				req.Messages = slices.Clone(req.Messages)
+				req.Messages[0].Content = "[redacted]"
+			}
+			return next(ctx, call)
+		}
+	}
+	WithMiddleware(trace("outer"), trace("inner"), redact)(client)
+
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "my password is hunter2"},
+		},
+	}
+	response, err := client.ChatCompletion(ctx, req)
+	a.NoError(err)
+	a.Equal("[redacted]", response.Choices[0].Message.Content)
+	a.Equal("my password is hunter2", req.Messages[0].Content)
+	a.Equal([]string{
+		"outer chat.completion",
+		"inner chat.completion",
+	}, calls)
+
+	// structured outputs go through the middlewares as well
+	calls = nil
+	var out any
+	_ = client.ChatCompletionJSON(ctx, req, &out)
+	a.Equal([]string{
+		"outer chat.completion",
+		"inner chat.completion",
+	}, calls)
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, _, teardown := setupGroqTestServer()
+	defer teardown()
+	cached := ChatCompletionResponse{ID: "chatcmpl-cached"}
+	WithMiddleware(Intercept(func(
+		_ context.Context,
+		_ *ChatCompletionRequest,
+		_ func(context.Context) (ChatCompletionResponse, error),
+	) (ChatCompletionResponse, error) {
+		return cached, nil
+	}))(client)
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Hello"},
+		},
+	}
+	response, err := client.ChatCompletion(ctx, req)
+	a.NoError(err)
+	a.Equal("chatcmpl-cached", response.ID)
+
+	WithMiddleware(func(Handler) Handler {
+		return func(context.Context, *Call) (any, error) {
+			return "not a response", nil
+		}
+	})(client)
+	_, err = client.ListModels(ctx)
+	a.ErrorContains(err, "middleware returned a string response")
+
+	client.middlewares = nil
+	WithMiddleware(func(Handler) Handler {
+		return func(context.Context, *Call) (any, error) {
+			return nil, errors.New("quota exceeded")
+		}
+	})(client)
+	_, err = client.ChatCompletion(ctx, req)
+	a.EqualError(err, "quota exceeded")
+}
+
+func TestMiddlewareStream(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		handleStreamEndpoint(t, toolCallChunks...),
+	)
+	var (
+		chunks int
+		end    error
+	)
+	WithMiddleware(Intercept(func(
+		ctx context.Context,
+		_ *ChatCompletionRequest,
+		next func(context.Context) (*ChatCompletionStream, error),
+	) (*ChatCompletionStream, error) {
+		stream, err := next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		stream.Observe(func(_ *ChatCompletionStreamResponse, err error) {
+			if err != nil {
+				end = err
+				return
+			}
+			chunks++
+		})
+		return stream, nil
+	}))(client)
+	stream, err := client.ChatCompletionStream(
+		context.Background(),
+		ChatCompletionRequest{
+			Model: ModelLlama3370BVersatile,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Hello"},
+			},
+		},
+	)
+	a.NoError(err)
+	_, err = stream.Accumulate()
+	a.NoError(err)
+	a.Equal(len(toolCallChunks), chunks)
+	a.ErrorIs(end, io.EOF)
+}
@@ -18,6 +18,9 @@ type (
 
 	// AudioModel is the type for audio models present on the groq api.
 	AudioModel Model
+
+	// EmbeddingModel is the type for embedding models present on the groq api.
+	EmbeddingModel Model
 )
 
 var (
@@ -0,0 +1,53 @@
+package cassette
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/conneroisu/groq-go/internal/test"
+)
+
+const (
+	// ModeReplay replays the interactions of a cassette without touching
+	// the network, failing the requests matching none of them.
+	ModeReplay = test.ModeReplay
+	// ModeRecord sends the requests through the transport of the recorder
+	// and records their interactions into a new cassette.
+	ModeRecord = test.ModeRecord
+)
+
+type (
+	// Mode is the mode of a Recorder.
+	Mode = test.Mode
+	// Cassette is a recording of the HTTP interactions of a test.
+	Cassette = test.Cassette
+	// Interaction is a recorded request and its response.
+	Interaction = test.Interaction
+	// RecordedRequest is a recorded request.
+	RecordedRequest = test.RecordedRequest
+	// RecordedResponse is a recorded response.
+	RecordedResponse = test.RecordedResponse
+	// Recorder is an http.RoundTripper recording the interactions of a
+	// test into a cassette file, or replaying them from it.
+	//
+	// Requests are matched to recorded interactions by method, path and
+	// normalized body, so that key order and multipart boundaries do not
+	// matter.
+	Recorder = test.Recorder
+)
+
+// NewRecorder returns a recorder of the cassette file at the path, see
+// Recorder.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	return test.NewRecorder(path, mode)
+}
+
+// NewClient returns an http client recording the interactions of the test
+// into the cassette file at the path when the UNIT environment variable is
+// set, and replaying them otherwise.
+//
+// The test is skipped if the cassette has not been recorded yet.
+func NewClient(t testing.TB, path string) *http.Client {
+	t.Helper()
+	return test.NewCassetteClient(t, path)
+}
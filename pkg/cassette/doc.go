@@ -0,0 +1,23 @@
+// Package cassette records the HTTP interactions of tests with the Groq API
+// into redacted cassette files and replays them offline.
+//
+//	func TestChat(t *testing.T) {
+//		// replayed cassettes need no key, their credentials are redacted
+//		key := "replay"
+//		if os.Getenv("UNIT") != "" {
+//			key = os.Getenv("GROQ_KEY")
+//		}
+//		client, err := groq.NewClient(
+//			key,
+//			groq.WithClient(cassette.NewClient(t, "testdata/chat.json")),
+//		)
+//		...
+//	}
+//
+// Cassettes are recorded against the live API when the UNIT environment
+// variable is set, and replayed otherwise.
+//
+// The tests of the chat-terminal and audio-house-translation examples
+// replay cassettes of a streamed chat completion and a multipart audio
+// translation.
+package cassette
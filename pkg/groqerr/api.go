@@ -10,6 +10,36 @@ type (
 	ErrToolNotFound struct {
 		ToolName string
 	}
+	// ErrUnsupportedByModel is returned when a request uses a capability
+	// that the requested model does not support.
+	ErrUnsupportedByModel struct {
+		Model      string
+		Capability string
+	}
+	// ErrAgentLimit is returned when an agent run reaches one of its
+	// limits before the model stops calling tools.
+	ErrAgentLimit struct {
+		Limit string
+		Value int
+	}
+	// ErrTruncated is returned when the completion of a response was cut
+	// short by the maximum number of tokens of the request or model.
+	ErrTruncated struct {
+		// ResponseID is the id of the truncated response.
+		ResponseID string
+	}
+	// ErrStructuredOutput is returned when the content of a choice cannot
+	// be parsed into the requested structured output.
+	ErrStructuredOutput struct {
+		// ResponseID is the id of the response holding the choice.
+		ResponseID string
+		// Choice is the index of the choice.
+		Choice int
+		// Content is the raw content of the choice.
+		Content string
+		// Err is the error that occurred while parsing the content.
+		Err error
+	}
 )
 
 // Error implements the error interface.
@@ -44,3 +74,40 @@ func (e *ErrRequest) Unwrap() error {
 func (e ErrToolNotFound) Error() string {
 	return fmt.Sprintf("tool %s not found", e.ToolName)
 }
+
+// Error implements the error interface.
+func (e *ErrUnsupportedByModel) Error() string {
+	return fmt.Sprintf(
+		"model %s does not support %s",
+		e.Model,
+		e.Capability,
+	)
+}
+
+// Error implements the error interface.
+func (e *ErrAgentLimit) Error() string {
+	return fmt.Sprintf("agent reached its %s of %d", e.Limit, e.Value)
+}
+
+// Error implements the error interface.
+func (e *ErrTruncated) Error() string {
+	return fmt.Sprintf(
+		"response %s was truncated by its token limit",
+		e.ResponseID,
+	)
+}
+
+// Error implements the error interface.
+func (e *ErrStructuredOutput) Error() string {
+	return fmt.Sprintf(
+		"parsing choice %d of response %s: %v",
+		e.Choice,
+		e.ResponseID,
+		e.Err,
+	)
+}
+
+// Unwrap unwraps the error.
+func (e *ErrStructuredOutput) Unwrap() error {
+	return e.Err
+}
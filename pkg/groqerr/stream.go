@@ -5,12 +5,19 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 )
 
 type (
 	// ErrTooManyEmptyStreamMessages is returned when the stream has sent
 	// too many empty messages.
 	ErrTooManyEmptyStreamMessages struct{}
+	// ErrStreamIdleTimeout is returned when no bytes of the stream arrived
+	// within its idle timeout.
+	ErrStreamIdleTimeout struct {
+		// IdleTimeout is the idle timeout that elapsed.
+		IdleTimeout time.Duration
+	}
 
 	// ErrorResponse is the response returned by the Groq API.
 	ErrorResponse struct {
@@ -92,3 +99,11 @@ func (e *APIError) UnmarshalJSON(data []byte) (err error) {
 func (e ErrTooManyEmptyStreamMessages) Error() string {
 	return "stream has sent too many empty messages"
 }
+
+// Error returns the error message.
+func (e ErrStreamIdleTimeout) Error() string {
+	return fmt.Sprintf("stream idle for more than %s", e.IdleTimeout)
+}
+
+// Timeout reports that the error is a timeout.
+func (e ErrStreamIdleTimeout) Timeout() bool { return true }
@@ -0,0 +1,13 @@
+// Package otelgroq instruments groq-go clients with OpenTelemetry traces and
+// metrics following the GenAI semantic conventions.
+//
+//	client, err := groq.NewClient(
+//		os.Getenv("GROQ_KEY"),
+//		otelgroq.WithTelemetry(),
+//	)
+//
+// It is a module of its own, so that only its users depend on
+// OpenTelemetry:
+//
+//	go get github.com/conneroisu/groq-go/pkg/otelgroq
+package otelgroq
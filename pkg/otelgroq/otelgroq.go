@@ -0,0 +1,353 @@
+package otelgroq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer and meter of the package.
+const instrumentationName = "github.com/conneroisu/groq-go/pkg/otelgroq"
+
+type (
+	// Option configures the instrumentation.
+	Option func(*config)
+	// config is the configuration of the instrumentation.
+	config struct {
+		tracerProvider trace.TracerProvider
+		meterProvider  metric.MeterProvider
+	}
+	// instruments are the tracer and metric instruments of the
+	// instrumentation.
+	instruments struct {
+		tracer           trace.Tracer
+		duration         metric.Float64Histogram
+		tokenUsage       metric.Int64Histogram
+		timeToFirstToken metric.Float64Histogram
+	}
+	// observation is the outcome of a call recorded on its span and
+	// metrics.
+	observation struct {
+		responseID       string
+		responseModel    string
+		finishReasons    []string
+		usage            *groq.Usage
+		timeToFirstToken time.Duration
+	}
+)
+
+// operationNames are the GenAI operation names of the instrumented calls.
+//
+// Operations without a name in the semantic conventions are named after
+// the endpoint they call.
+var operationNames = map[groq.Operation]attribute.KeyValue{
+	groq.OperationChatCompletion:       semconv.GenAIOperationNameChat,
+	groq.OperationChatCompletionStream: semconv.GenAIOperationNameChat,
+	groq.OperationEmbedding:            semconv.GenAIOperationNameEmbeddings,
+	groq.OperationModeration:           operationName("moderation"),
+	groq.OperationTranscription:        operationName("transcription"),
+	groq.OperationTranslation:          operationName("translation"),
+}
+
+// operationName returns a GenAI operation name attribute.
+func operationName(name string) attribute.KeyValue {
+	return semconv.GenAIOperationNameKey.String(name)
+}
+
+// WithTracerProvider sets the tracer provider of the instrumentation.
+//
+// The global tracer provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = provider }
+}
+
+// WithMeterProvider sets the meter provider of the instrumentation.
+//
+// The global meter provider is used by default.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) { c.meterProvider = provider }
+}
+
+// WithTelemetry instruments a Groq client with OpenTelemetry, see
+// Middleware.
+func WithTelemetry(opts ...Option) groq.Opts {
+	return groq.WithMiddleware(Middleware(opts...))
+}
+
+// Middleware returns a middleware recording a span and metrics for each
+// chat completion, streamed chat completion, moderation, embedding,
+// transcription and translation call of a client.
+//
+// Spans carry the GenAI semantic convention attributes of the call: the
+// requested and responding model, the response id, the finish reasons and
+// the token usage. Streamed chat completions end their span once the
+// stream ends and record the time to their first chunk.
+//
+// The recorded metrics are the gen_ai.client.operation.duration and
+// gen_ai.client.token.usage histograms of the GenAI semantic conventions,
+// along with a gen_ai.client.time_to_first_token histogram for streams.
+func Middleware(opts ...Option) groq.Middleware {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	meter := c.meterProvider.Meter(instrumentationName)
+	inst := &instruments{
+		tracer: c.tracerProvider.Tracer(instrumentationName),
+	}
+	var err error
+	inst.duration, err = meter.Float64Histogram(
+		"gen_ai.client.operation.duration",
+		metric.WithDescription("GenAI operation duration."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst.tokenUsage, err = meter.Int64Histogram(
+		"gen_ai.client.token.usage",
+		metric.WithDescription(
+			"Measures number of input and output tokens used.",
+		),
+		metric.WithUnit("{token}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst.timeToFirstToken, err = meter.Float64Histogram(
+		"gen_ai.client.time_to_first_token",
+		metric.WithDescription(
+			"Time to the first chunk of a streamed completion.",
+		),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return func(next groq.Handler) groq.Handler {
+		return func(ctx context.Context, call *groq.Call) (any, error) {
+			name, ok := operationNames[call.Operation]
+			if !ok {
+				return next(ctx, call)
+			}
+			return inst.handle(ctx, call, name, next)
+		}
+	}
+}
+
+// handle performs an instrumented call.
+func (inst *instruments) handle(
+	ctx context.Context,
+	call *groq.Call,
+	operationName attribute.KeyValue,
+	next groq.Handler,
+) (any, error) {
+	attrs := []attribute.KeyValue{
+		semconv.GenAISystemGroq,
+		operationName,
+		semconv.GenAIRequestModel(call.Model),
+	}
+	ctx, span := inst.tracer.Start(
+		ctx,
+		fmt.Sprintf("%s %s", operationName.Value.AsString(), call.Model),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(requestAttributes(call)...),
+	)
+	start := time.Now()
+	res, err := next(ctx, call)
+	if err != nil {
+		inst.end(ctx, span, attrs, start, observation{}, err)
+		return res, err
+	}
+	switch res := res.(type) {
+	case *groq.ChatCompletionStream:
+		inst.observeStream(ctx, span, attrs, start, res)
+	case groq.ChatCompletionResponse:
+		obs := observation{
+			responseID:    res.ID,
+			responseModel: string(res.Model),
+			usage:         &res.Usage,
+		}
+		for _, choice := range res.Choices {
+			obs.finishReasons = append(
+				obs.finishReasons,
+				string(choice.FinishReason),
+			)
+		}
+		inst.end(ctx, span, attrs, start, obs, nil)
+	case groq.ModerationResponse:
+		inst.end(ctx, span, attrs, start, observation{
+			usage: &res.Usage,
+		}, nil)
+	case groq.EmbeddingResponse:
+		inst.end(ctx, span, attrs, start, observation{
+			responseModel: string(res.Model),
+			usage:         &res.Usage,
+		}, nil)
+	default:
+		inst.end(ctx, span, attrs, start, observation{}, nil)
+	}
+	return res, err
+}
+
+// observeStream ends the span of a streamed chat completion once the
+// stream ends.
+func (inst *instruments) observeStream(
+	ctx context.Context,
+	span trace.Span,
+	attrs []attribute.KeyValue,
+	start time.Time,
+	stream *groq.ChatCompletionStream,
+) {
+	var (
+		mu  sync.Mutex
+		obs observation
+	)
+	reasons := map[int]string{}
+	stream.Observe(func(chunk *groq.ChatCompletionStreamResponse, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			for i := range len(reasons) {
+				obs.finishReasons = append(obs.finishReasons, reasons[i])
+			}
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrClosedPipe) {
+				err = nil
+			}
+			inst.end(ctx, span, attrs, start, obs, err)
+			return
+		}
+		if obs.timeToFirstToken == 0 {
+			obs.timeToFirstToken = time.Since(start)
+		}
+		if obs.responseID == "" {
+			obs.responseID = chunk.ID
+			obs.responseModel = string(chunk.Model)
+		}
+		if chunk.Usage != nil {
+			obs.usage = chunk.Usage
+		}
+		if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+			obs.usage = chunk.XGroq.Usage
+		}
+		for _, choice := range chunk.Choices {
+			if choice.FinishReason != "" {
+				reasons[choice.Index] = string(choice.FinishReason)
+			}
+		}
+	})
+}
+
+// end records the outcome of a call on its span and metrics, and ends the
+// span.
+func (inst *instruments) end(
+	ctx context.Context,
+	span trace.Span,
+	attrs []attribute.KeyValue,
+	start time.Time,
+	obs observation,
+	err error,
+) {
+	if obs.responseModel != "" {
+		attrs = append(attrs, semconv.GenAIResponseModel(obs.responseModel))
+	}
+	if err != nil {
+		errType := semconv.ErrorTypeOther
+		var apiErr *groqerr.APIError
+		if errors.As(err, &apiErr) {
+			switch {
+			case apiErr.Type != "":
+				errType = semconv.ErrorTypeKey.String(apiErr.Type)
+			case apiErr.HTTPStatusCode > 0:
+				errType = semconv.ErrorTypeKey.String(
+					strconv.Itoa(apiErr.HTTPStatusCode),
+				)
+			}
+		}
+		attrs = append(attrs, errType)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.SetAttributes(attrs...)
+	if obs.responseID != "" {
+		span.SetAttributes(semconv.GenAIResponseID(obs.responseID))
+	}
+	if len(obs.finishReasons) > 0 {
+		span.SetAttributes(
+			semconv.GenAIResponseFinishReasons(obs.finishReasons...),
+		)
+	}
+	set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+	if obs.usage != nil {
+		span.SetAttributes(
+			semconv.GenAIUsageInputTokens(obs.usage.PromptTokens),
+			semconv.GenAIUsageOutputTokens(obs.usage.CompletionTokens),
+		)
+		inst.tokenUsage.Record(
+			ctx,
+			int64(obs.usage.PromptTokens),
+			set,
+			metric.WithAttributes(semconv.GenAITokenTypeInput),
+		)
+		inst.tokenUsage.Record(
+			ctx,
+			int64(obs.usage.CompletionTokens),
+			set,
+			metric.WithAttributes(semconv.GenAITokenTypeOutput),
+		)
+	}
+	if obs.timeToFirstToken > 0 {
+		span.SetAttributes(attribute.Float64(
+			"gen_ai.response.time_to_first_token",
+			obs.timeToFirstToken.Seconds(),
+		))
+		inst.timeToFirstToken.Record(ctx, obs.timeToFirstToken.Seconds(), set)
+	}
+	inst.duration.Record(ctx, time.Since(start).Seconds(), set)
+	span.End()
+}
+
+// requestAttributes returns the attributes of the request of a call.
+func requestAttributes(call *groq.Call) []attribute.KeyValue {
+	req, ok := call.Request.(*groq.ChatCompletionRequest)
+	if !ok {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	if req.MaxTokens > 0 {
+		attrs = append(attrs, semconv.GenAIRequestMaxTokens(req.MaxTokens))
+	}
+	if req.Temperature != 0 {
+		attrs = append(
+			attrs,
+			semconv.GenAIRequestTemperature(float64(req.Temperature)),
+		)
+	}
+	if req.TopP != 0 {
+		attrs = append(attrs, semconv.GenAIRequestTopP(float64(req.TopP)))
+	}
+	if req.N > 1 {
+		attrs = append(attrs, semconv.GenAIRequestChoiceCount(req.N))
+	}
+	if req.Seed != nil {
+		attrs = append(attrs, semconv.GenAIRequestSeed(*req.Seed))
+	}
+	return attrs
+}
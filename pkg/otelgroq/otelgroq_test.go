@@ -0,0 +1,278 @@
+package otelgroq
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go"
+	"github.com/conneroisu/groq-go/internal/test"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// setupTelemetry returns an instrumented client of a test server along with
+// the exporter of its spans and the reader of its metrics.
+func setupTelemetry(t *testing.T) (
+	client *groq.Client,
+	server *test.ServerTest,
+	spans *tracetest.InMemoryExporter,
+	metrics *sdkmetric.ManualReader,
+) {
+	server = test.NewTestServer()
+	ts := server.GroqTestServer()
+	ts.Start()
+	t.Cleanup(ts.Close)
+	spans = tracetest.NewInMemoryExporter()
+	metrics = sdkmetric.NewManualReader()
+	client, err := groq.NewClient(
+		test.GetTestToken(),
+		groq.WithBaseURL(ts.URL+"/v1"),
+		WithTelemetry(
+			WithTracerProvider(sdktrace.NewTracerProvider(
+				sdktrace.WithSyncer(spans),
+			)),
+			WithMeterProvider(sdkmetric.NewMeterProvider(
+				sdkmetric.WithReader(metrics),
+			)),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+// attributes returns the attributes of a span keyed by name.
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+// histograms returns the number of data points recorded by each histogram.
+func histograms(
+	t *testing.T,
+	reader *sdkmetric.ManualReader,
+) map[string]uint64 {
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]uint64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					counts[m.Name] += dp.Count
+				}
+			case metricdata.Histogram[int64]:
+				for _, dp := range data.DataPoints {
+					counts[m.Name] += dp.Count
+				}
+			}
+		}
+	}
+	return counts
+}
+
+func TestChatCompletion(t *testing.T) {
+	a := assert.New(t)
+	client, server, spans, metrics := setupTelemetry(t)
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			err := json.NewEncoder(w).Encode(groq.ChatCompletionResponse{
+				ID:    "chatcmpl-1",
+				Model: groq.ModelLlama3370BVersatile,
+				Choices: []groq.ChatCompletionChoice{{
+					Message: groq.ChatCompletionMessage{
+						Role:    groq.RoleAssistant,
+						Content: "Hello!",
+					},
+					FinishReason: groq.ReasonStop,
+				}},
+				Usage: groq.Usage{
+					PromptTokens:     12,
+					CompletionTokens: 3,
+					TotalTokens:      15,
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	_, err := client.ChatCompletion(
+		context.Background(),
+		groq.ChatCompletionRequest{
+			Model:       groq.ModelLlama3370BVersatile,
+			MaxTokens:   64,
+			Temperature: 0.5,
+			Messages: []groq.ChatCompletionMessage{
+				{Role: groq.RoleUser, Content: "Hello"},
+			},
+		},
+	)
+	a.NoError(err)
+
+	stubs := spans.GetSpans()
+	a.Len(stubs, 1)
+	span := stubs[0]
+	a.Equal("chat "+string(groq.ModelLlama3370BVersatile), span.Name)
+	a.Equal(trace.SpanKindClient, span.SpanKind)
+	a.Equal(codes.Unset, span.Status.Code)
+	attrs := attributes(span)
+	a.Equal("groq", attrs["gen_ai.system"].AsString())
+	a.Equal("chat", attrs["gen_ai.operation.name"].AsString())
+	a.Equal(
+		string(groq.ModelLlama3370BVersatile),
+		attrs["gen_ai.request.model"].AsString(),
+	)
+	a.Equal(int64(64), attrs["gen_ai.request.max_tokens"].AsInt64())
+	a.Equal(0.5, attrs["gen_ai.request.temperature"].AsFloat64())
+	a.Equal("chatcmpl-1", attrs["gen_ai.response.id"].AsString())
+	a.Equal(
+		[]string{"stop"},
+		attrs["gen_ai.response.finish_reasons"].AsStringSlice(),
+	)
+	a.Equal(int64(12), attrs["gen_ai.usage.input_tokens"].AsInt64())
+	a.Equal(int64(3), attrs["gen_ai.usage.output_tokens"].AsInt64())
+
+	a.Equal(map[string]uint64{
+		"gen_ai.client.operation.duration": 1,
+		"gen_ai.client.token.usage":        2,
+	}, histograms(t, metrics))
+}
+
+func TestChatCompletionStream(t *testing.T) {
+	a := assert.New(t)
+	client, server, spans, metrics := setupTelemetry(t)
+	chunks := []string{
+		`{"id":"chatcmpl-2","model":"llama-3.3-70b-versatile",` +
+			`"choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+		`{"id":"chatcmpl-2","model":"llama-3.3-70b-versatile",` +
+			`"choices":[{"index":0,"delta":{"content":"lo"},` +
+			`"finish_reason":"stop"}],"x_groq":{"usage":` +
+			`{"prompt_tokens":7,"completion_tokens":2,"total_tokens":9}}}`,
+	}
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			var b strings.Builder
+			for _, chunk := range chunks {
+				b.WriteString("data: " + chunk + "\n\n")
+			}
+			b.WriteString("data: [DONE]\n\n")
+			_, err := io.WriteString(w, b.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	stream, err := client.ChatCompletionStream(
+		context.Background(),
+		groq.ChatCompletionRequest{
+			Model: groq.ModelLlama3370BVersatile,
+			Messages: []groq.ChatCompletionMessage{
+				{Role: groq.RoleUser, Content: "Hello"},
+			},
+			Stream: true,
+		},
+	)
+	a.NoError(err)
+	a.Empty(spans.GetSpans(), "the span ends with the stream")
+	for _, err := range stream.All() {
+		a.NoError(err)
+	}
+
+	stubs := spans.GetSpans()
+	a.Len(stubs, 1)
+	span := stubs[0]
+	a.Equal(codes.Unset, span.Status.Code)
+	attrs := attributes(span)
+	a.Equal("chatcmpl-2", attrs["gen_ai.response.id"].AsString())
+	a.Equal(
+		[]string{"stop"},
+		attrs["gen_ai.response.finish_reasons"].AsStringSlice(),
+	)
+	a.Equal(int64(7), attrs["gen_ai.usage.input_tokens"].AsInt64())
+	a.Equal(int64(2), attrs["gen_ai.usage.output_tokens"].AsInt64())
+	a.Positive(attrs["gen_ai.response.time_to_first_token"].AsFloat64())
+
+	a.Equal(map[string]uint64{
+		"gen_ai.client.operation.duration":  1,
+		"gen_ai.client.token.usage":         2,
+		"gen_ai.client.time_to_first_token": 1,
+	}, histograms(t, metrics))
+}
+
+func TestError(t *testing.T) {
+	a := assert.New(t)
+	client, server, spans, metrics := setupTelemetry(t)
+	server.RegisterHandler(
+		"/v1/embeddings",
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, err := io.WriteString(
+				w,
+				`{"error":{"message":"bad input",`+
+					`"type":"invalid_request_error"}}`,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	_, err := client.Embed(context.Background(), groq.EmbeddingRequest{
+		Model: "nomic-embed-text-v1.5",
+		Input: []string{"Hello"},
+	})
+	a.Error(err)
+
+	stubs := spans.GetSpans()
+	a.Len(stubs, 1)
+	span := stubs[0]
+	a.Equal("embeddings nomic-embed-text-v1.5", span.Name)
+	a.Equal(codes.Error, span.Status.Code)
+	a.Len(span.Events, 1)
+	attrs := attributes(span)
+	a.Equal("400", attrs["error.type"].AsString())
+	a.Equal(map[string]uint64{
+		"gen_ai.client.operation.duration": 1,
+	}, histograms(t, metrics))
+}
+
+func TestUninstrumented(t *testing.T) {
+	a := assert.New(t)
+	client, server, spans, _ := setupTelemetry(t)
+	server.RegisterHandler(
+		"/v1/models",
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, err := io.WriteString(w, `{"object":"list","data":[]}`)
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	_, err := client.ListModels(context.Background())
+	a.NoError(err)
+	a.Empty(spans.GetSpans())
+}
@@ -0,0 +1,303 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+
+	"github.com/conneroisu/groq-go/internal/omap"
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+// Builder builds a schema by hand through chained calls.
+//
+//	s := schema.Object().
+//		Property("city", schema.String().Description("The city.")).
+//		Property("days", schema.Integer().Minimum(1).Maximum(7)).
+//		Required("city").
+//		Build()
+type Builder struct {
+	s *Schema
+}
+
+// New returns a builder of a schema without constraints.
+func New() *Builder {
+	return &Builder{s: &Schema{}}
+}
+
+// From returns a builder refining a deep copy of an existing schema, e.g.
+// one reflected from a type, leaving the schema untouched.
+func From(s *Schema) *Builder {
+	return &Builder{s: s.Clone()}
+}
+
+// Object returns a builder of an object schema.
+func Object() *Builder {
+	return New().Type("object")
+}
+
+// String returns a builder of a string schema.
+func String() *Builder {
+	return New().Type("string")
+}
+
+// Integer returns a builder of an integer schema.
+func Integer() *Builder {
+	return New().Type("integer")
+}
+
+// Number returns a builder of a number schema.
+func Number() *Builder {
+	return New().Type("number")
+}
+
+// Boolean returns a builder of a boolean schema.
+func Boolean() *Builder {
+	return New().Type("boolean")
+}
+
+// Null returns a builder of a null schema.
+func Null() *Builder {
+	return New().Type("null")
+}
+
+// Array returns a builder of an array schema whose items match the given
+// schema.
+func Array(items *Builder) *Builder {
+	return New().Type("array").Items(items)
+}
+
+// Ref returns a builder of a schema referencing another, e.g.
+// "#/$defs/address".
+func Ref(ref string) *Builder {
+	b := New()
+	b.s.Ref = ref
+	return b
+}
+
+// Build returns the built schema.
+//
+// The builder must not be used afterwards.
+func (b *Builder) Build() *Schema {
+	return b.s
+}
+
+// FunctionParameters returns the built schema as the parameters of a tool
+// function.
+func (b *Builder) FunctionParameters() (tools.FunctionParameters, error) {
+	return ToFunctionParameters(b.s)
+}
+
+// Type sets the type of the schema.
+func (b *Builder) Type(typ string) *Builder {
+	b.s.Type = typ
+	return b
+}
+
+// Title sets the title of the schema.
+func (b *Builder) Title(title string) *Builder {
+	b.s.Title = title
+	return b
+}
+
+// Description sets the description of the schema.
+func (b *Builder) Description(description string) *Builder {
+	b.s.Description = description
+	return b
+}
+
+// Enum restricts the values of the schema to the given ones.
+func (b *Builder) Enum(values ...any) *Builder {
+	b.s.Enum = append(b.s.Enum, values...)
+	return b
+}
+
+// Const restricts the value of the schema to the given one.
+func (b *Builder) Const(value any) *Builder {
+	b.s.Const = value
+	return b
+}
+
+// Default sets the default value of the schema.
+func (b *Builder) Default(value any) *Builder {
+	b.s.Default = value
+	return b
+}
+
+// Examples adds examples of values of the schema.
+func (b *Builder) Examples(values ...any) *Builder {
+	b.s.Examples = append(b.s.Examples, values...)
+	return b
+}
+
+// Format sets the format of a string schema, e.g. "date-time".
+func (b *Builder) Format(format string) *Builder {
+	b.s.Format = format
+	return b
+}
+
+// Pattern sets the regular expression a string schema must match.
+func (b *Builder) Pattern(pattern string) *Builder {
+	b.s.Pattern = pattern
+	return b
+}
+
+// MinLength sets the minimum length of a string schema.
+func (b *Builder) MinLength(n uint64) *Builder {
+	b.s.MinLength = &n
+	return b
+}
+
+// MaxLength sets the maximum length of a string schema.
+func (b *Builder) MaxLength(n uint64) *Builder {
+	b.s.MaxLength = &n
+	return b
+}
+
+// Minimum sets the inclusive minimum of a numeric schema.
+func (b *Builder) Minimum(n float64) *Builder {
+	b.s.Minimum = number(n)
+	return b
+}
+
+// Maximum sets the inclusive maximum of a numeric schema.
+func (b *Builder) Maximum(n float64) *Builder {
+	b.s.Maximum = number(n)
+	return b
+}
+
+// ExclusiveMinimum sets the exclusive minimum of a numeric schema.
+func (b *Builder) ExclusiveMinimum(n float64) *Builder {
+	b.s.ExclusiveMinimum = number(n)
+	return b
+}
+
+// ExclusiveMaximum sets the exclusive maximum of a numeric schema.
+func (b *Builder) ExclusiveMaximum(n float64) *Builder {
+	b.s.ExclusiveMaximum = number(n)
+	return b
+}
+
+// MultipleOf sets the number the values of a numeric schema must be a
+// multiple of.
+func (b *Builder) MultipleOf(n float64) *Builder {
+	b.s.MultipleOf = number(n)
+	return b
+}
+
+// Items sets the schema of the items of an array schema.
+func (b *Builder) Items(items *Builder) *Builder {
+	b.s.Items = items.Build()
+	return b
+}
+
+// MinItems sets the minimum number of items of an array schema.
+func (b *Builder) MinItems(n uint64) *Builder {
+	b.s.MinItems = &n
+	return b
+}
+
+// MaxItems sets the maximum number of items of an array schema.
+func (b *Builder) MaxItems(n uint64) *Builder {
+	b.s.MaxItems = &n
+	return b
+}
+
+// UniqueItems requires the items of an array schema to be unique.
+func (b *Builder) UniqueItems() *Builder {
+	b.s.UniqueItems = true
+	return b
+}
+
+// Property sets the schema of a property of an object schema.
+//
+// Properties keep the order in which they are set.
+func (b *Builder) Property(name string, property *Builder) *Builder {
+	if b.s.Properties == nil {
+		b.s.Properties = omap.New[string, *Schema]()
+	}
+	b.s.Properties.Set(name, property.Build())
+	return b
+}
+
+// Required marks properties of an object schema as required.
+func (b *Builder) Required(names ...string) *Builder {
+	for _, name := range names {
+		if !slices.Contains(b.s.Required, name) {
+			b.s.Required = append(b.s.Required, name)
+		}
+	}
+	return b
+}
+
+// AdditionalProperties sets the schema of the properties of an object
+// schema that are not declared.
+func (b *Builder) AdditionalProperties(property *Builder) *Builder {
+	b.s.AdditionalProperties = property.Build()
+	return b
+}
+
+// NoAdditionalProperties disallows the properties of an object schema that
+// are not declared.
+func (b *Builder) NoAdditionalProperties() *Builder {
+	b.s.AdditionalProperties = Bool(false)
+	return b
+}
+
+// Def adds a definition to the schema, which can be referenced by
+// "#/$defs/<name>".
+func (b *Builder) Def(name string, def *Builder) *Builder {
+	if b.s.Definitions == nil {
+		b.s.Definitions = map[string]*Schema{}
+	}
+	b.s.Definitions[name] = def.Build()
+	return b
+}
+
+// AllOf requires values of the schema to match all the given schemas.
+func (b *Builder) AllOf(schemas ...*Builder) *Builder {
+	b.s.AllOf = append(b.s.AllOf, build(schemas)...)
+	return b
+}
+
+// AnyOf requires values of the schema to match any of the given schemas.
+func (b *Builder) AnyOf(schemas ...*Builder) *Builder {
+	b.s.AnyOf = append(b.s.AnyOf, build(schemas)...)
+	return b
+}
+
+// OneOf requires values of the schema to match exactly one of the given
+// schemas.
+func (b *Builder) OneOf(schemas ...*Builder) *Builder {
+	b.s.OneOf = append(b.s.OneOf, build(schemas)...)
+	return b
+}
+
+// Not requires values of the schema not to match the given schema.
+func (b *Builder) Not(schema *Builder) *Builder {
+	b.s.Not = schema.Build()
+	return b
+}
+
+// Extra sets a keyword of the schema that has no method of its own.
+func (b *Builder) Extra(key string, value any) *Builder {
+	if b.s.Extras == nil {
+		b.s.Extras = map[string]any{}
+	}
+	b.s.Extras[key] = value
+	return b
+}
+
+// build builds the schemas of the given builders.
+func build(builders []*Builder) []*Schema {
+	schemas := make([]*Schema, len(builders))
+	for i, b := range builders {
+		schemas[i] = b.Build()
+	}
+	return schemas
+}
+
+// number returns a float as a JSON number.
+func number(n float64) json.Number {
+	return json.Number(strconv.FormatFloat(n, 'f', -1, 64))
+}
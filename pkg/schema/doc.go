@@ -0,0 +1,3 @@
+// Package schema provides JSON Schemas for structured outputs and tool
+// parameters, either reflected from Go types or built by hand.
+package schema
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"encoding/json"
+
+	"github.com/conneroisu/groq-go/internal/schema"
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+type (
+	// Schema is a JSON Schema, e.g. the schema of a structured output set
+	// as the Schema of a groq.JSONSchema.
+	Schema = schema.Schema
+	// Reflector reflects Go types into schemas.
+	//
+	// Its fields configure the reflection, e.g. RequiredFromJSONSchemaTags
+	// to require only the fields tagged `jsonschema:"required"`,
+	// DoNotReference to inline nested types instead of referencing their
+	// $defs, Mapper to map custom types to schemas and KeyNamer to rename
+	// properties.
+	Reflector = schema.Reflector
+	// ID is the URI identifying a schema.
+	ID = schema.ID
+	// Violation is a construct of a schema that the strict mode of
+	// structured outputs rejects, as reported by Schema.Lint.
+	Violation = schema.Violation
+	// Violations are the constructs of a schema that the strict mode of
+	// structured outputs rejects.
+	Violations = schema.Violations
+)
+
+// Reflect reflects the schema of the type of a value with the default
+// reflector configuration.
+func Reflect(v any) *Schema {
+	return new(Reflector).Reflect(v)
+}
+
+// Bool returns the boolean schema accepting any value when v is true and
+// no value otherwise.
+func Bool(v bool) *Schema {
+	return schema.Bool(v)
+}
+
+// ToFunctionParameters converts a schema into the parameters of a tool
+// function.
+func ToFunctionParameters(s *Schema) (tools.FunctionParameters, error) {
+	var params tools.FunctionParameters
+	b, err := json.Marshal(s)
+	if err != nil {
+		return params, err
+	}
+	err = json.Unmarshal(b, &params)
+	if err != nil {
+		return params, err
+	}
+	if params.Properties == nil {
+		params.Properties = map[string]tools.PropertyDefinition{}
+	}
+	return params, nil
+}
+
+// FromFunctionParameters converts the parameters of a tool function into a
+// schema.
+func FromFunctionParameters(params tools.FunctionParameters) (*Schema, error) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	s := new(Schema)
+	err = json.Unmarshal(b, s)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type forecast struct {
+	City string `json:"city" jsonschema:"required,description=The city."`
+	Days int    `json:"days,omitempty" jsonschema:"minimum=1,maximum=7"`
+	Unit string `json:"unit"`
+}
+
+func TestBuilder(t *testing.T) {
+	s := Object().
+		Property("city", String().Description("The city.")).
+		Property("days", Integer().Minimum(1).Maximum(7)).
+		Property("unit", String().Enum("celsius", "fahrenheit")).
+		Property("tags", Array(String()).MaxItems(3)).
+		Required("city", "city").
+		NoAdditionalProperties().
+		Build()
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"city": {"type": "string", "description": "The city."},
+			"days": {"type": "integer", "minimum": 1, "maximum": 7},
+			"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3}
+		},
+		"required": ["city"],
+		"additionalProperties": false
+	}`, string(b))
+	// properties keep the order in which they are set
+	assert.Less(
+		t,
+		strings.Index(string(b), `"city"`),
+		strings.Index(string(b), `"tags"`),
+	)
+
+	assert.Error(t, s.Validate([]byte(`{"days":9}`)))
+	assert.NoError(t, s.Validate([]byte(`{"city":"Paris","days":3}`)))
+}
+
+func TestBuilderRefs(t *testing.T) {
+	s := Object().
+		Def("address", Object().
+			Property("street", String()).
+			Required("street")).
+		Property("home", Ref("#/$defs/address")).
+		Build()
+	assert.NoError(t, s.Validate([]byte(`{"home":{"street":"Main"}}`)))
+	assert.Error(t, s.Validate([]byte(`{"home":{}}`)))
+}
+
+func TestReflector(t *testing.T) {
+	r := &Reflector{
+		Anonymous:                  true,
+		DoNotReference:             true,
+		RequiredFromJSONSchemaTags: true,
+		KeyNamer:                   strings.ToUpper,
+		Mapper: func(t reflect.Type) *Schema {
+			if t.Kind() == reflect.Int {
+				return String().Pattern("^[0-9]+$").Build()
+			}
+			return nil
+		},
+	}
+	s := r.Reflect(forecast{})
+	assert.Equal(t, []string{"CITY"}, s.Required)
+	days, ok := s.Properties.Get("DAYS")
+	require.True(t, ok)
+	assert.Equal(t, "string", days.Type)
+	assert.Equal(t, "^[0-9]+$", days.Pattern)
+
+	refined := From(s).Title("forecast").Build()
+	assert.Equal(t, "forecast", refined.Title)
+	assert.Empty(t, s.Title)
+}
+
+func TestFrom(t *testing.T) {
+	s := (&Reflector{DoNotReference: true}).Reflect(forecast{})
+	before, err := json.Marshal(s)
+	require.NoError(t, err)
+	refined := From(s).
+		Property("country", String()).
+		Required("unit").
+		NoAdditionalProperties().
+		Build()
+	days, ok := refined.Properties.Get("days")
+	require.True(t, ok)
+	days.Description = "The number of days."
+	days.Enum = append(days.Enum, 1)
+
+	after, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(before), string(after))
+	_, ok = s.Properties.Get("country")
+	assert.False(t, ok)
+}
+
+func TestFunctionParameters(t *testing.T) {
+	params, err := Object().
+		Property("city", String().Description("The city.")).
+		Property("days", Integer().Minimum(1)).
+		Required("city").
+		FunctionParameters()
+	require.NoError(t, err)
+	assert.Equal(t, "object", params.Type)
+	assert.Equal(t, []string{"city"}, params.Required)
+	assert.Equal(t, tools.PropertyDefinition{
+		Type:        "string",
+		Description: "The city.",
+	}, params.Properties["city"])
+	assert.Equal(t, 1.0, *params.Properties["days"].Minimum)
+
+	s, err := FromFunctionParameters(params)
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate([]byte(`{"city":"Paris","days":3}`)))
+	assert.Error(t, s.Validate([]byte(`{"days":0}`)))
+
+	empty, err := ToFunctionParameters(Object().Build())
+	require.NoError(t, err)
+	assert.NotNil(t, empty.Properties)
+}
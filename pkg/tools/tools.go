@@ -1,5 +1,14 @@
 package tools
 
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
 const (
 	// ToolTypeFunction is the function tool type.
 	ToolTypeFunction ToolType = "function"
@@ -37,15 +46,67 @@ type (
 	}
 	// FunctionParameters represents the function parameters of a tool.
 	FunctionParameters struct {
-		Type                 string                        `json:"type"`
-		Properties           map[string]PropertyDefinition `json:"properties"`
-		Required             []string                      `json:"required"`
-		AdditionalProperties bool                          `json:"additionalProperties,omitempty"`
+		Type       string                        `json:"type"`
+		Properties map[string]PropertyDefinition `json:"properties"`
+		Required   []string                      `json:"required"`
+		// AdditionalProperties allows parameters without a property.
+		AdditionalProperties bool `json:"additionalProperties,omitempty"`
+		// AdditionalPropertiesSchema is the schema of the parameters
+		// without a property, e.g. BoolProperty(false) to forbid them.
+		//
+		// It takes precedence over AdditionalProperties when set.
+		AdditionalPropertiesSchema *PropertyDefinition `json:"-"`
+		// Defs holds the definitions referenced by the properties through
+		// their Ref.
+		Defs map[string]PropertyDefinition `json:"$defs,omitempty"`
 	}
 	// PropertyDefinition represents the property definition.
+	//
+	// It is a JSON Schema, so properties can declare enums, nested objects,
+	// array items, defaults and bounds.
+	//
+	// Keywords without a field are kept in Extras so that schemas received
+	// from tool providers round-trip without losing detail.
 	PropertyDefinition struct {
-		Type        string `json:"type"`
-		Description string `json:"description"`
+		Type string `json:"type,omitempty"`
+		// Types are the types of a property accepting several, e.g. a
+		// nullable string, sent as an array in place of Type.
+		Types       []string `json:"-"`
+		Description string   `json:"description,omitempty"`
+		Title       string   `json:"title,omitempty"`
+		Ref         string   `json:"$ref,omitempty"`
+		Enum        []any    `json:"enum,omitempty"`
+		Const       any      `json:"const,omitempty"`
+		Default     any      `json:"default,omitempty"`
+		Examples    []any    `json:"examples,omitempty"`
+		// string keywords
+		Format    string `json:"format,omitempty"`
+		Pattern   string `json:"pattern,omitempty"`
+		MinLength *int   `json:"minLength,omitempty"`
+		MaxLength *int   `json:"maxLength,omitempty"`
+		// numeric keywords
+		Minimum          *float64 `json:"minimum,omitempty"`
+		Maximum          *float64 `json:"maximum,omitempty"`
+		ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+		MultipleOf       *float64 `json:"multipleOf,omitempty"`
+		// array keywords
+		Items       *PropertyDefinition `json:"items,omitempty"`
+		MinItems    *int                `json:"minItems,omitempty"`
+		MaxItems    *int                `json:"maxItems,omitempty"`
+		UniqueItems bool                `json:"uniqueItems,omitempty"`
+		// object keywords
+		Properties           map[string]PropertyDefinition `json:"properties,omitempty"`
+		Required             []string                      `json:"required,omitempty"`
+		AdditionalProperties *PropertyDefinition           `json:"additionalProperties,omitempty"`
+		// composition keywords
+		AllOf []PropertyDefinition `json:"allOf,omitempty"`
+		AnyOf []PropertyDefinition `json:"anyOf,omitempty"`
+		OneOf []PropertyDefinition `json:"oneOf,omitempty"`
+		// Extras holds the keywords of the schema without a field.
+		Extras map[string]any `json:"-"`
+
+		boolean *bool
 	}
 	// ToolCall represents a tool call.
 	ToolCall struct {
@@ -66,3 +127,153 @@ type (
 		Arguments string `json:"arguments,omitempty"`
 	}
 )
+
+// propertyKeywords are the keywords of a PropertyDefinition with a field.
+var propertyKeywords = func() map[string]bool {
+	keywords := map[string]bool{}
+	t := reflect.TypeFor[PropertyDefinition]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keywords[name] = true
+		}
+	}
+	return keywords
+}()
+
+// BoolProperty returns the boolean schema of the given value.
+//
+// The true schema accepts any value and the false schema none, e.g. an
+// AdditionalPropertiesSchema of BoolProperty(false) forbids additional
+// parameters.
+func BoolProperty(v bool) *PropertyDefinition {
+	return &PropertyDefinition{boolean: &v}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p FunctionParameters) MarshalJSON() ([]byte, error) {
+	type alias FunctionParameters
+	v := struct {
+		alias
+		AdditionalProperties *PropertyDefinition `json:"additionalProperties,omitempty"`
+	}{alias: alias(p)}
+	switch {
+	case p.AdditionalPropertiesSchema != nil:
+		v.AdditionalProperties = p.AdditionalPropertiesSchema
+	case p.AdditionalProperties:
+		v.AdditionalProperties = BoolProperty(true)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// An additionalProperties of true sets AdditionalProperties, any other
+// schema sets AdditionalPropertiesSchema.
+func (p *FunctionParameters) UnmarshalJSON(data []byte) error {
+	type alias FunctionParameters
+	var v struct {
+		alias
+		AdditionalProperties *PropertyDefinition `json:"additionalProperties,omitempty"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*p = FunctionParameters(v.alias)
+	additional := v.AdditionalProperties
+	if additional != nil && additional.boolean != nil && *additional.boolean {
+		p.AdditionalProperties = true
+	} else {
+		p.AdditionalPropertiesSchema = additional
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (p PropertyDefinition) MarshalJSON() ([]byte, error) {
+	if p.boolean != nil {
+		return json.Marshal(*p.boolean)
+	}
+	type alias PropertyDefinition
+	v := struct {
+		alias
+		Type any `json:"type,omitempty"`
+	}{alias: alias(p)}
+	switch {
+	case len(p.Types) > 0:
+		v.Type = p.Types
+	case p.Type != "":
+		v.Type = p.Type
+	}
+	b, err := json.Marshal(v)
+	if err != nil || len(p.Extras) == 0 {
+		return b, err
+	}
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	for _, key := range slices.Sorted(maps.Keys(p.Extras)) {
+		if propertyKeywords[key] {
+			continue
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(p.Extras[key])
+		if err != nil {
+			return nil, err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *PropertyDefinition) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		*p = PropertyDefinition{boolean: &boolean}
+		return nil
+	}
+	type alias PropertyDefinition
+	var v struct {
+		alias
+		Type json.RawMessage `json:"type,omitempty"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	a := v.alias
+	if len(v.Type) > 0 && v.Type[0] == '[' {
+		if err := json.Unmarshal(v.Type, &a.Types); err != nil {
+			return err
+		}
+	} else if len(v.Type) > 0 {
+		if err := json.Unmarshal(v.Type, &a.Type); err != nil {
+			return err
+		}
+	}
+	var keywords map[string]any
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for key, value := range keywords {
+		if propertyKeywords[key] {
+			continue
+		}
+		if a.Extras == nil {
+			a.Extras = map[string]any{}
+		}
+		a.Extras[key] = value
+	}
+	*p = PropertyDefinition(a)
+	return nil
+}
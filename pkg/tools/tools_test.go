@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPropertyDefinitionJSON(t *testing.T) {
+	a := assert.New(t)
+	data := `{
+		"type": "object",
+		"properties": {
+			"unit": {
+				"type": "string",
+				"enum": ["celsius", "fahrenheit"],
+				"default": "celsius"
+			},
+			"days": {
+				"type": "array",
+				"items": {"type": "integer", "minimum": 1, "maximum": 7},
+				"maxItems": 3
+			},
+			"location": {
+				"type": "object",
+				"properties": {
+					"city": {"type": "string"},
+					"zip": {"type": ["string", "null"]}
+				},
+				"required": ["city"],
+				"additionalProperties": false,
+				"x-display": "map"
+			}
+		},
+		"required": ["unit"],
+		"additionalProperties": false
+	}`
+	var params FunctionParameters
+	a.NoError(json.Unmarshal([]byte(data), &params))
+	unit := params.Properties["unit"]
+	a.Equal([]any{"celsius", "fahrenheit"}, unit.Enum)
+	a.Equal("celsius", unit.Default)
+	days := params.Properties["days"]
+	a.Equal("integer", days.Items.Type)
+	a.Equal(7.0, *days.Items.Maximum)
+	a.Equal(3, *days.MaxItems)
+	location := params.Properties["location"]
+	a.Equal("string", location.Properties["city"].Type)
+	a.Equal([]string{"string", "null"}, location.Properties["zip"].Types)
+	a.Equal(BoolProperty(false), location.AdditionalProperties)
+	a.Equal(map[string]any{"x-display": "map"}, location.Extras)
+	a.False(params.AdditionalProperties)
+	a.Equal(BoolProperty(false), params.AdditionalPropertiesSchema)
+
+	b, err := json.Marshal(params)
+	a.NoError(err)
+	a.JSONEq(data, string(b))
+}
+
+func TestPropertyDefinitionLiteral(t *testing.T) {
+	a := assert.New(t)
+	b, err := json.Marshal(PropertyDefinition{
+		Type:        "string",
+		Description: "The path of the directory to create",
+	})
+	a.NoError(err)
+	a.JSONEq(
+		`{"type":"string","description":"The path of the directory to create"}`,
+		string(b),
+	)
+	b, err = json.Marshal(PropertyDefinition{
+		Extras: map[string]any{"x-order": 1},
+	})
+	a.NoError(err)
+	a.JSONEq(`{"x-order":1}`, string(b))
+}
+
+func TestFunctionParametersAdditionalProperties(t *testing.T) {
+	a := assert.New(t)
+	b, err := json.Marshal(FunctionParameters{
+		Type:                 "object",
+		AdditionalProperties: false,
+	})
+	a.NoError(err)
+	a.JSONEq(`{"type":"object","properties":null,"required":null}`, string(b))
+	b, err = json.Marshal(FunctionParameters{
+		Type:                 "object",
+		AdditionalProperties: true,
+	})
+	a.NoError(err)
+	a.JSONEq(
+		`{"type":"object","properties":null,"required":null,`+
+			`"additionalProperties":true}`,
+		string(b),
+	)
+	b, err = json.Marshal(FunctionParameters{
+		Type:                       "object",
+		AdditionalPropertiesSchema: &PropertyDefinition{Type: "string"},
+	})
+	a.NoError(err)
+	a.JSONEq(
+		`{"type":"object","properties":null,"required":null,`+
+			`"additionalProperties":{"type":"string"}}`,
+		string(b),
+	)
+	var params FunctionParameters
+	a.NoError(json.Unmarshal(b, &params))
+	a.Equal("string", params.AdditionalPropertiesSchema.Type)
+	a.NoError(json.Unmarshal([]byte(`{"additionalProperties":true}`), &params))
+	a.True(params.AdditionalProperties)
+	a.Nil(params.AdditionalPropertiesSchema)
+}
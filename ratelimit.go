@@ -0,0 +1,105 @@
+package groq
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit is the rate limit information returned by the Groq API in the
+// x-ratelimit-* headers of a response.
+//
+// Fields are left zero, or nil for the remaining counts, when the
+// corresponding header is absent or malformed, so that a missing header is
+// not mistaken for an exhausted limit.
+//
+// https://console.groq.com/docs/rate-limits
+type RateLimit struct {
+	// LimitRequests is the maximum number of requests allowed per day.
+	LimitRequests int `json:"x-ratelimit-limit-requests"`
+	// LimitTokens is the maximum number of tokens allowed per minute.
+	LimitTokens int `json:"x-ratelimit-limit-tokens"`
+	// RemainingRequests is the number of requests remaining before the
+	// request limit is reached.
+	RemainingRequests *int `json:"x-ratelimit-remaining-requests"`
+	// RemainingTokens is the number of tokens remaining before the token
+	// limit is reached.
+	RemainingTokens *int `json:"x-ratelimit-remaining-tokens"`
+	// ResetRequests is the time until the request limit resets.
+	ResetRequests time.Duration `json:"x-ratelimit-reset-requests"`
+	// ResetTokens is the time until the token limit resets.
+	ResetTokens time.Duration `json:"x-ratelimit-reset-tokens"`
+}
+
+// newRateLimit parses the rate limit headers of a response.
+func newRateLimit(h http.Header) RateLimit {
+	atoi := func(key string) int {
+		v, _ := strconv.Atoi(h.Get(key))
+		return v
+	}
+	remaining := func(key string) *int {
+		v, err := strconv.Atoi(h.Get(key))
+		if err != nil {
+			return nil
+		}
+		return &v
+	}
+	duration := func(key string) time.Duration {
+		v, _ := time.ParseDuration(h.Get(key))
+		return v
+	}
+	return RateLimit{
+		LimitRequests:     atoi("x-ratelimit-limit-requests"),
+		LimitTokens:       atoi("x-ratelimit-limit-tokens"),
+		RemainingRequests: remaining("x-ratelimit-remaining-requests"),
+		RemainingTokens:   remaining("x-ratelimit-remaining-tokens"),
+		ResetRequests:     duration("x-ratelimit-reset-requests"),
+		ResetTokens:       duration("x-ratelimit-reset-tokens"),
+	}
+}
+
+// RateLimit returns the rate limit information of the response.
+func (r *ChatCompletionResponse) RateLimit() RateLimit {
+	return newRateLimit(r.header)
+}
+
+// RequestsExhausted reports whether the API reported no remaining
+// requests.
+func (rl RateLimit) RequestsExhausted() bool {
+	return rl.RemainingRequests != nil && *rl.RemainingRequests == 0
+}
+
+// TokensExhausted reports whether the API reported no remaining tokens.
+func (rl RateLimit) TokensExhausted() bool {
+	return rl.RemainingTokens != nil && *rl.RemainingTokens == 0
+}
+
+// RateLimit returns the rate limit information of the response.
+func (r *ModerationResponse) RateLimit() RateLimit {
+	return newRateLimit(r.header)
+}
+
+// RateLimit returns the rate limit information of the response.
+func (r *AudioResponse) RateLimit() RateLimit {
+	return newRateLimit(r.header)
+}
+
+// RateLimit returns the rate limit information of the response.
+func (r *EmbeddingResponse) RateLimit() RateLimit {
+	return newRateLimit(r.header)
+}
+
+// RateLimit returns the rate limit information of the response.
+func (r *ModelList) RateLimit() RateLimit {
+	return newRateLimit(r.header)
+}
+
+// RateLimit returns the rate limit information of the response.
+func (r *ModelMetadata) RateLimit() RateLimit {
+	return newRateLimit(r.header)
+}
+
+// RateLimit returns the rate limit information of the stream's response.
+func (s *ChatCompletionStream) RateLimit() RateLimit {
+	return newRateLimit(s.Header)
+}
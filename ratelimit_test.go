@@ -0,0 +1,97 @@
+package groq
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	setRateLimitHeaders := func(w http.ResponseWriter) {
+		w.Header().Set("x-ratelimit-limit-requests", "14400")
+		w.Header().Set("x-ratelimit-limit-tokens", "18000")
+		w.Header().Set("x-ratelimit-remaining-requests", "14370")
+		w.Header().Set("x-ratelimit-remaining-tokens", "17997")
+		w.Header().Set("x-ratelimit-reset-requests", "2m59.56s")
+		w.Header().Set("x-ratelimit-reset-tokens", "7.66s")
+	}
+	remainingRequests, remainingTokens := 14370, 17997
+	expected := RateLimit{
+		LimitRequests:     14400,
+		LimitTokens:       18000,
+		RemainingRequests: &remainingRequests,
+		RemainingTokens:   &remainingTokens,
+		ResetRequests:     2*time.Minute + 59560*time.Millisecond,
+		ResetTokens:       7660 * time.Millisecond,
+	}
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			setRateLimitHeaders(w)
+			if r.Header.Get("Accept") == "text/event-stream" {
+				w.Header().Set("Content-Type", "text/event-stream")
+				_, _ = w.Write([]byte("data: [DONE]\n\n"))
+				return
+			}
+			handleModerationEndpoint(w, r)
+		},
+	)
+	server.RegisterHandler(
+		"/v1/audio/transcriptions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			setRateLimitHeaders(w)
+			_, _ = w.Write([]byte(`{"text": "hello"}`))
+		},
+	)
+	req := ChatCompletionRequest{
+		Model: ModelLlama38B8192,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Hello!"},
+		},
+	}
+	resp, err := client.ChatCompletion(ctx, req)
+	a.NoError(err)
+	a.Equal(expected, resp.RateLimit())
+
+	stream, err := client.ChatCompletionStream(ctx, req)
+	a.NoError(err)
+	defer stream.Close()
+	a.Equal(expected, stream.RateLimit())
+
+	audio, err := client.Transcribe(ctx, AudioRequest{
+		Model:    ModelWhisperLargeV3,
+		FilePath: "fake.mp3",
+		Reader:   http.NoBody,
+	})
+	a.NoError(err)
+	a.Equal(expected, audio.RateLimit())
+
+	moderation, err := client.CreateModeration(ctx, ModerationRequest{
+		Model:    ModelLlamaGuard38B,
+		Messages: req.Messages,
+	})
+	a.NoError(err)
+	a.Equal(expected, moderation.RateLimit())
+}
+
+func TestRateLimitMissingHeaders(t *testing.T) {
+	a := assert.New(t)
+	rl := newRateLimit(http.Header{})
+	a.Nil(rl.RemainingRequests)
+	a.Nil(rl.RemainingTokens)
+	a.False(rl.RequestsExhausted())
+	a.False(rl.TokensExhausted())
+
+	h := http.Header{}
+	h.Set("x-ratelimit-remaining-tokens", "0")
+	rl = newRateLimit(h)
+	a.Nil(rl.RemainingRequests)
+	a.True(rl.TokensExhausted())
+}
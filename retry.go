@@ -0,0 +1,244 @@
+package groq
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+type (
+	// RetryPolicy configures how a Client retries failed requests.
+	//
+	// Zero valued fields, except Jitter, are replaced by the values of
+	// DefaultRetryPolicy when the policy is applied with WithRetryPolicy.
+	RetryPolicy struct {
+		// MaxAttempts is the maximum number of attempts made for a
+		// request, including the first one.
+		//
+		// Set it to 1 to disable retries.
+		MaxAttempts int
+		// InitialBackoff is the delay before the first retry.
+		InitialBackoff time.Duration
+		// MaxBackoff caps the computed exponential backoff.
+		//
+		// A retryable response whose Retry-After or x-ratelimit-reset-*
+		// headers request a longer delay, e.g. once the daily request
+		// limit is exhausted, is returned without being retried.
+		MaxBackoff time.Duration
+		// Multiplier is the factor the backoff grows by after each
+		// attempt.
+		Multiplier float64
+		// Jitter is the fraction, between 0 and 1, of the computed
+		// backoff that is randomized. A zero Jitter disables
+		// randomization.
+		Jitter float64
+		// RetryableStatusCodes are the http status codes that trigger a
+		// retry.
+		RetryableStatusCodes []int
+	}
+)
+
+// DefaultRetryPolicy returns the retry policy used by a Client when none is
+// configured.
+//
+// It makes at most 3 attempts, backing off exponentially from 500ms up to
+// 30s, and retries rate limited (429) and server error (500, 502, 503, 504)
+// responses.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// withDefaults returns the policy with its zero valued fields replaced by
+// the values of DefaultRetryPolicy.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	def := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = def.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = def.MaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = def.Multiplier
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = def.Jitter
+	}
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = def.RetryableStatusCodes
+	}
+	return p
+}
+
+// retryable reports whether the given status code should be retried.
+func (p RetryPolicy) retryable(statusCode int) bool {
+	return slices.Contains(p.RetryableStatusCodes, statusCode)
+}
+
+// backoff returns the delay before the given retry attempt (starting at 1).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff) *
+		math.Pow(p.Multiplier, float64(attempt-1))
+	if d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	d -= d * p.Jitter * rand.Float64()
+	return time.Duration(d)
+}
+
+// withRetryDelay overrides the initial backoff of the retry policy for a
+// request, unless the delay is zero.
+func withRetryDelay(d time.Duration) sendOption {
+	return func(o *sendOptions) { o.retryDelay = d }
+}
+
+// do sends the request, retrying it according to the client's retry policy.
+//
+// The returned response is the one of the last attempt made.
+func (c *Client) do(
+	req *http.Request,
+	setters ...sendOption,
+) (*http.Response, error) {
+	var opts sendOptions
+	for _, setter := range setters {
+		setter(&opts)
+	}
+	policy := c.retryPolicy
+	if opts.retryDelay > 0 {
+		policy.InitialBackoff = opts.retryDelay
+	}
+	if req.Body != nil && req.GetBody == nil {
+		// the body can not be replayed
+		policy.MaxAttempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		c.logger.DebugContext(
+			req.Context(),
+			"sending groq request",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"attempt", attempt,
+		)
+		start := time.Now()
+		res, err := c.client.Do(req)
+		if err == nil {
+			c.logger.DebugContext(
+				req.Context(),
+				"received groq response",
+				"url", req.URL.String(),
+				"attempt", attempt,
+				"status", res.StatusCode,
+				"duration", time.Since(start),
+			)
+		}
+		if attempt >= policy.MaxAttempts {
+			return res, err
+		}
+		delay := policy.backoff(attempt)
+		switch {
+		case err != nil:
+			if req.Context().Err() != nil {
+				return nil, err
+			}
+			c.logger.WarnContext(
+				req.Context(),
+				"retrying groq request",
+				"url", req.URL.String(),
+				"attempt", attempt,
+				"delay", delay,
+				"error", err,
+			)
+		case policy.retryable(res.StatusCode):
+			hint, ok := retryAfter(res.Header)
+			if ok && hint > policy.MaxBackoff {
+				return res, nil
+			}
+			if ok && hint > delay {
+				delay = hint
+			}
+			_, _ = io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+			c.logger.WarnContext(
+				req.Context(),
+				"retrying groq request",
+				"url", req.URL.String(),
+				"attempt", attempt,
+				"delay", delay,
+				"status", res.StatusCode,
+			)
+		default:
+			return res, nil
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryAfter returns the delay requested by the server through the
+// Retry-After header, or through the x-ratelimit-reset-* headers of an
+// exhausted rate limit.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil {
+			return time.Duration(secs * float64(time.Second)), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return time.Until(t), true
+		}
+	}
+	var (
+		rl    = newRateLimit(h)
+		delay time.Duration
+	)
+	if rl.RequestsExhausted() {
+		delay = rl.ResetRequests
+	}
+	if rl.TokensExhausted() {
+		delay = max(delay, rl.ResetTokens)
+	}
+	return delay, delay > 0
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,214 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go/internal/test"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	server := test.NewTestServer()
+	ts := server.GroqTestServer()
+	ts.Start()
+	defer ts.Close()
+	var calls atomic.Int32
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			err := json.NewEncoder(w).Encode(ChatCompletionResponse{
+				ID: "chatcmpl-123",
+				Choices: []ChatCompletionChoice{{
+					Message: ChatCompletionMessage{
+						Role:    RoleAssistant,
+						Content: "Hello!",
+					},
+				}},
+			})
+			a.NoError(err)
+		},
+	)
+	t.Run("retries until success", func(t *testing.T) {
+		a := assert.New(t)
+		calls.Store(0)
+		client, err := NewClient(
+			test.GetTestToken(),
+			WithBaseURL(ts.URL+"/v1"),
+			WithRetryPolicy(RetryPolicy{
+				InitialBackoff: time.Millisecond,
+				MaxAttempts:    3,
+			}),
+		)
+		a.NoError(err)
+		resp, err := client.ChatCompletion(ctx, ChatCompletionRequest{
+			Model: ModelLlama38B8192,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Hello!"},
+			},
+		})
+		a.NoError(err)
+		a.Equal("Hello!", resp.Choices[0].Message.Content)
+		a.EqualValues(3, calls.Load())
+	})
+	t.Run("stops after max attempts", func(t *testing.T) {
+		a := assert.New(t)
+		calls.Store(0)
+		client, err := NewClient(
+			test.GetTestToken(),
+			WithBaseURL(ts.URL+"/v1"),
+			WithRetryPolicy(RetryPolicy{
+				InitialBackoff: time.Millisecond,
+				MaxAttempts:    2,
+			}),
+		)
+		a.NoError(err)
+		_, err = client.ChatCompletion(ctx, ChatCompletionRequest{
+			Model: ModelLlama38B8192,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Hello!"},
+			},
+		})
+		var reqErr *groqerr.ErrRequest
+		a.ErrorAs(err, &reqErr)
+		a.Equal(http.StatusTooManyRequests, reqErr.HTTPStatusCode)
+		a.EqualValues(2, calls.Load())
+	})
+	t.Run("request retry delay", func(t *testing.T) {
+		a := assert.New(t)
+		calls.Store(0)
+		client, err := NewClient(
+			test.GetTestToken(),
+			WithBaseURL(ts.URL+"/v1"),
+			WithRetryPolicy(RetryPolicy{
+				InitialBackoff: time.Hour,
+				MaxBackoff:     time.Hour,
+			}),
+		)
+		a.NoError(err)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		_, err = client.ChatCompletion(ctx, ChatCompletionRequest{
+			Model: ModelLlama38B8192,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Hello!"},
+			},
+			RetryDelay: time.Millisecond,
+		})
+		a.NoError(err)
+		a.EqualValues(3, calls.Load())
+	})
+	t.Run("context cancelled while sleeping", func(t *testing.T) {
+		a := assert.New(t)
+		calls.Store(0)
+		client, err := NewClient(
+			test.GetTestToken(),
+			WithBaseURL(ts.URL+"/v1"),
+			WithRetryPolicy(RetryPolicy{
+				InitialBackoff: time.Hour,
+				MaxBackoff:     time.Hour,
+			}),
+		)
+		a.NoError(err)
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err = client.ChatCompletion(ctx, ChatCompletionRequest{
+			Model: ModelLlama38B8192,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Hello!"},
+			},
+		})
+		a.ErrorIs(err, context.DeadlineExceeded)
+		a.EqualValues(1, calls.Load())
+	})
+	t.Run("hint beyond max backoff", func(t *testing.T) {
+		a := assert.New(t)
+		server := test.NewTestServer()
+		ts := server.GroqTestServer()
+		ts.Start()
+		defer ts.Close()
+		var calls atomic.Int32
+		server.RegisterHandler(
+			"/v1/chat/completions",
+			func(w http.ResponseWriter, _ *http.Request) {
+				calls.Add(1)
+				w.Header().Set("x-ratelimit-remaining-requests", "0")
+				w.Header().Set("x-ratelimit-reset-requests", "2h30m")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+		)
+		client, err := NewClient(
+			test.GetTestToken(),
+			WithBaseURL(ts.URL+"/v1"),
+			WithRetryPolicy(RetryPolicy{
+				InitialBackoff: time.Millisecond,
+				MaxBackoff:     time.Second,
+			}),
+		)
+		a.NoError(err)
+		_, err = client.ChatCompletion(ctx, ChatCompletionRequest{
+			Model: ModelLlama38B8192,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Hello!"},
+			},
+		})
+		var reqErr *groqerr.ErrRequest
+		a.ErrorAs(err, &reqErr)
+		a.Equal(http.StatusTooManyRequests, reqErr.HTTPStatusCode)
+		a.EqualValues(1, calls.Load())
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	a := assert.New(t)
+	h := http.Header{}
+	_, ok := retryAfter(h)
+	a.False(ok)
+
+	h.Set("Retry-After", "2")
+	d, ok := retryAfter(h)
+	a.True(ok)
+	a.Equal(2*time.Second, d)
+
+	h = http.Header{}
+	h.Set("x-ratelimit-remaining-requests", "10")
+	h.Set("x-ratelimit-reset-requests", "1s")
+	h.Set("x-ratelimit-remaining-tokens", "0")
+	h.Set("x-ratelimit-reset-tokens", "7.66s")
+	d, ok = retryAfter(h)
+	a.True(ok)
+	a.Equal(7660*time.Millisecond, d)
+
+	// resets are only waited for when the remaining count was sent
+	h = http.Header{}
+	h.Set("x-ratelimit-reset-requests", "1s")
+	h.Set("x-ratelimit-reset-tokens", "7.66s")
+	_, ok = retryAfter(h)
+	a.False(ok)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	a := assert.New(t)
+	p := RetryPolicy{
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+	}.withDefaults()
+	a.Equal(time.Second, p.backoff(1))
+	a.Equal(2*time.Second, p.backoff(2))
+	a.Equal(4*time.Second, p.backoff(3))
+	a.Equal(5*time.Second, p.backoff(4))
+}
@@ -0,0 +1,114 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+// ServeChatCompletionStream writes the chunks of the stream to w as a
+// server-sent events response, e.g. to proxy a stream to a browser.
+//
+// Each chunk is written as a data event and flushed. Once the stream ends,
+// a "usage" event holding the usage of the completion is written, if the
+// stream reported it, followed by a "[DONE]" data event. An error ending
+// the stream is written as an "error" event and returned.
+//
+// The stream is closed when the client disconnects, cancelling the
+// upstream request, in which case the error of the request context is
+// returned.
+func ServeChatCompletionStream(
+	w http.ResponseWriter,
+	r *http.Request,
+	stream *ChatCompletionStream,
+) error {
+	stop := context.AfterFunc(r.Context(), func() { _ = stream.Close() })
+	defer stop()
+	rc := http.NewResponseController(w)
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	var usage *Usage
+	for {
+		chunk, err := stream.Recv()
+		if r.Context().Err() != nil {
+			return r.Context().Err()
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var apiErr *groqerr.APIError
+			if !errors.As(err, &apiErr) {
+				apiErr = &groqerr.APIError{Message: err.Error()}
+			}
+			_ = writeEvent(w, rc, "error", groqerr.ErrorResponse{
+				Error: apiErr,
+			})
+			return err
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		if chunk.XGroq != nil && chunk.XGroq.Usage != nil {
+			usage = chunk.XGroq.Usage
+		}
+		err = writeEvent(w, rc, "", chunk)
+		if err != nil {
+			return err
+		}
+	}
+	if usage != nil {
+		err := writeEvent(w, rc, "usage", usage)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "data: [DONE]\n\n")
+	if err != nil {
+		return err
+	}
+	return flush(rc)
+}
+
+// writeEvent writes a server-sent event of the given type holding v as
+// JSON, and flushes it.
+func writeEvent(
+	w io.Writer,
+	rc *http.ResponseController,
+	event string,
+	v any,
+) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if event != "" {
+		_, err = fmt.Fprintf(w, "event: %s\n", event)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	if err != nil {
+		return err
+	}
+	return flush(rc)
+}
+
+// flush flushes the response, if the response writer supports it.
+func flush(rc *http.ResponseController) error {
+	err := rc.Flush()
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeChatCompletionStream(t *testing.T) {
+	a := assert.New(t)
+	stream, _ := newTestStream(streamBody(toolCallChunks...))
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/chat", nil)
+	a.NoError(ServeChatCompletionStream(w, r, stream))
+	a.True(w.Flushed)
+	a.Equal("text/event-stream", w.Header().Get("Content-Type"))
+
+	d := streams.NewEventDecoder(w.Body)
+	var events []streams.Event
+	for {
+		event, err := d.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		a.NoError(err)
+		events = append(events, event)
+	}
+	a.Len(events, len(toolCallChunks)+2)
+	usage := events[len(events)-2]
+	a.Equal("usage", usage.Type)
+	a.JSONEq(
+		`{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}`,
+		string(usage.Data),
+	)
+	a.Equal("[DONE]", string(events[len(events)-1].Data))
+}
+
+func TestServeChatCompletionStreamError(t *testing.T) {
+	a := assert.New(t)
+	stream, _ := newTestStream(
+		"data: " + toolCallChunks[0] + "\n\n" +
+			`data: {"error":{"message":"overloaded","type":"server_error"}}` +
+			"\n\n",
+	)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/chat", nil)
+	a.ErrorContains(ServeChatCompletionStream(w, r, stream), "overloaded")
+	d := streams.NewEventDecoder(w.Body)
+	_, err := d.Next()
+	a.NoError(err)
+	event, err := d.Next()
+	a.NoError(err)
+	a.Equal("error", event.Type)
+	a.Contains(string(event.Data), "overloaded")
+}
+
+func TestServeChatCompletionStreamDisconnect(t *testing.T) {
+	a := assert.New(t)
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	stream := &ChatCompletionStream{
+		streams.NewStreamReader[ChatCompletionStreamResponse](pr, nil, 10),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := httptest.NewRecorder()
+	r := httptest.NewRequestWithContext(ctx, "GET", "/chat", nil)
+	go func() {
+		_, _ = io.WriteString(pw, "data: "+toolCallChunks[0]+"\n\n")
+		cancel()
+	}()
+	a.ErrorIs(ServeChatCompletionStream(w, r, stream), context.Canceled)
+	// the stream was closed, cancelling the upstream request
+	_, err := pw.Write([]byte("data: {}\n\n"))
+	a.ErrorIs(err, io.ErrClosedPipe)
+}
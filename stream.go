@@ -0,0 +1,176 @@
+package groq
+
+import (
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+type (
+	// ChatCompletionBroadcaster fans the chunks of a chat completion
+	// stream out to several subscribers with bounded buffering.
+	ChatCompletionBroadcaster = streams.Broadcaster[*ChatCompletionStreamResponse]
+	// textReader reads the text of the first choice of a stream.
+	textReader struct {
+		stream  *ChatCompletionStream
+		pending []byte
+	}
+)
+
+// All returns an iterator over the chunks of the stream.
+//
+// Iteration stops after the first error, which is yielded. The stream is
+// closed once the loop exits, including when it exits early.
+//
+//	for chunk, err := range stream.All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Print(chunk.Choices[0].Delta.Content)
+//	}
+func (s *ChatCompletionStream) All() iter.Seq2[
+	*ChatCompletionStreamResponse, error,
+] {
+	return func(yield func(*ChatCompletionStreamResponse, error) bool) {
+		defer s.Close()
+		for chunk, err := range s.chunks() {
+			if !yield(chunk, err) {
+				return
+			}
+		}
+	}
+}
+
+// Text returns an iterator over the text deltas of the first choice of the
+// stream.
+//
+// Chunks without content are skipped. The stream is closed once the loop
+// exits.
+func (s *ChatCompletionStream) Text() iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		defer s.Close()
+		for chunk, err := range s.chunks() {
+			if err != nil {
+				yield("", err)
+				return
+			}
+			for _, choice := range chunk.Choices {
+				if choice.Index != 0 || choice.Delta.Content == "" {
+					continue
+				}
+				if !yield(choice.Delta.Content, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ToolCalls returns an iterator over the completed tool calls of the
+// stream.
+//
+// A tool call is yielded once its arguments are complete, that is once the
+// next tool call of its choice starts, its choice finishes or the stream
+// ends. The stream is closed once the loop exits.
+func (s *ChatCompletionStream) ToolCalls() iter.Seq2[tools.ToolCall, error] {
+	return func(yield func(tools.ToolCall, error) bool) {
+		defer s.Close()
+		var (
+			acc     ChatCompletionAccumulator
+			yielded = map[int]int{}
+		)
+		// flush yields the first n tool calls of a choice not yet yielded.
+		flush := func(index, n int) bool {
+			calls := acc.response.Choices[index].Message.ToolCalls
+			for ; yielded[index] < n; yielded[index]++ {
+				if !yield(calls[yielded[index]], nil) {
+					return false
+				}
+			}
+			return true
+		}
+		for chunk, err := range s.chunks() {
+			if err != nil {
+				yield(tools.ToolCall{}, err)
+				return
+			}
+			acc.Add(chunk)
+			for _, choice := range chunk.Choices {
+				n := len(acc.response.Choices[choice.Index].Message.ToolCalls)
+				if choice.FinishReason == "" {
+					n--
+				}
+				if !flush(choice.Index, n) {
+					return
+				}
+			}
+		}
+		for index, choice := range acc.response.Choices {
+			if !flush(index, len(choice.Message.ToolCalls)) {
+				return
+			}
+		}
+	}
+}
+
+// TextReader returns a reader of the text of the first choice of the
+// stream, e.g. to copy it into an http.ResponseWriter.
+//
+// Closing the reader closes the stream.
+func (s *ChatCompletionStream) TextReader() io.ReadCloser {
+	return &textReader{stream: s}
+}
+
+// Broadcaster returns a broadcaster of the chunks of the stream buffering
+// up to buffer chunks per subscriber.
+//
+// Subscribe before calling Run on the broadcaster to receive every chunk.
+func (s *ChatCompletionStream) Broadcaster(
+	buffer int,
+) *ChatCompletionBroadcaster {
+	return streams.NewBroadcaster(s.StreamReader, buffer)
+}
+
+// Read implements the io.Reader interface.
+func (r *textReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Index == 0 {
+				r.pending = append(r.pending, choice.Delta.Content...)
+			}
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// Close implements the io.Closer interface.
+func (r *textReader) Close() error {
+	return r.stream.Close()
+}
+
+// chunks returns an iterator over the chunks of the stream that leaves it
+// open.
+func (s *ChatCompletionStream) chunks() iter.Seq2[
+	*ChatCompletionStreamResponse, error,
+] {
+	return func(yield func(*ChatCompletionStreamResponse, error) bool) {
+		for {
+			chunk, err := s.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(chunk, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package groq
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/conneroisu/groq-go/internal/streams"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+// closeRecorder is a reader recording whether it was closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+// newTestStream creates a stream of the given body.
+func newTestStream(body string) (*ChatCompletionStream, *closeRecorder) {
+	rc := &closeRecorder{Reader: strings.NewReader(body)}
+	return &ChatCompletionStream{
+		streams.NewStreamReader[ChatCompletionStreamResponse](rc, nil, 10),
+	}, rc
+}
+
+// streamBody returns the body of a stream of the given chunks.
+func streamBody(chunks ...string) string {
+	var b strings.Builder
+	for _, chunk := range chunks {
+		b.WriteString("data: " + chunk + "\n\n")
+	}
+	b.WriteString("data: [DONE]\n\n")
+	return b.String()
+}
+
+func TestChatCompletionStreamAll(t *testing.T) {
+	a := assert.New(t)
+	stream, rc := newTestStream(streamBody(toolCallChunks...))
+	var ids []string
+	for chunk, err := range stream.All() {
+		a.NoError(err)
+		ids = append(ids, chunk.ID)
+	}
+	a.Len(ids, len(toolCallChunks))
+	a.True(rc.closed)
+
+	stream, rc = newTestStream(streamBody(toolCallChunks...))
+	for range stream.All() {
+		break
+	}
+	a.True(rc.closed)
+
+	stream, rc = newTestStream("data: {\"id\":\n\n")
+	var errs int
+	for _, err := range stream.All() {
+		a.Error(err)
+		errs++
+	}
+	a.Equal(1, errs)
+	a.True(rc.closed)
+}
+
+func TestChatCompletionStreamText(t *testing.T) {
+	a := assert.New(t)
+	stream, rc := newTestStream(streamBody(
+		`{"choices":[{"index":0,"delta":{"role":"assistant"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"Hello"}},{"index":1,"delta":{"content":"Hi"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":" world"}}]}`,
+	))
+	var text []string
+	for delta, err := range stream.Text() {
+		a.NoError(err)
+		text = append(text, delta)
+	}
+	a.Equal([]string{"Hello", " world"}, text)
+	a.True(rc.closed)
+}
+
+func TestChatCompletionStreamToolCalls(t *testing.T) {
+	a := assert.New(t)
+	stream, rc := newTestStream(streamBody(toolCallChunks...))
+	var calls []tools.ToolCall
+	for call, err := range stream.ToolCalls() {
+		a.NoError(err)
+		calls = append(calls, call)
+	}
+	a.Equal([]tools.ToolCall{
+		{
+			ID:   "call_1",
+			Type: "function",
+			Function: tools.FunctionCall{
+				Name:      "get_weather",
+				Arguments: `{"city":"Paris"}`,
+			},
+		},
+		{
+			ID:   "call_2",
+			Type: "function",
+			Function: tools.FunctionCall{
+				Name:      "get_time",
+				Arguments: "{}",
+			},
+		},
+	}, calls)
+	a.True(rc.closed)
+
+	// tool calls of unfinished choices are yielded at the end of the stream
+	stream, _ = newTestStream(streamBody(toolCallChunks[:2]...))
+	calls = nil
+	for call, err := range stream.ToolCalls() {
+		a.NoError(err)
+		calls = append(calls, call)
+	}
+	a.Len(calls, 1)
+	a.Equal(`{"city":`, calls[0].Function.Arguments)
+}
+
+func TestChatCompletionStreamIdleTimeout(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, err := io.WriteString(w, "data: "+toolCallChunks[0]+"\n\n")
+			assert.NoError(t, err)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		},
+	)
+	stream, err := client.ChatCompletionStream(
+		context.Background(),
+		ChatCompletionRequest{
+			Model: ModelLlama3370BVersatile,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Hello!"},
+			},
+			StreamIdleTimeout: 50 * time.Millisecond,
+		},
+	)
+	a.NoError(err)
+	defer stream.Close()
+	chunk, err := stream.Recv()
+	a.NoError(err)
+	a.Equal("chatcmpl-1", chunk.ID)
+	_, err = stream.Recv()
+	a.ErrorIs(err, groqerr.ErrStreamIdleTimeout{
+		IdleTimeout: 50 * time.Millisecond,
+	})
+}
+
+func TestChatCompletionStreamTextReader(t *testing.T) {
+	a := assert.New(t)
+	stream, rc := newTestStream(streamBody(
+		`{"choices":[{"index":0,"delta":{"role":"assistant"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"Hello"}},{"index":1,"delta":{"content":"Hi"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":" world"}}]}`,
+	))
+	r := stream.TextReader()
+	text, err := io.ReadAll(r)
+	a.NoError(err)
+	a.Equal("Hello world", string(text))
+	a.NoError(r.Close())
+	a.True(rc.closed)
+}
+
+func TestChatCompletionStreamBroadcaster(t *testing.T) {
+	a := assert.New(t)
+	stream, rc := newTestStream(streamBody(toolCallChunks...))
+	b := stream.Broadcaster(1)
+	chunks, cancel := b.Subscribe()
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() { errs <- b.Run(context.Background()) }()
+	var n int
+	for range chunks {
+		n++
+	}
+	a.Equal(len(toolCallChunks), n)
+	a.NoError(<-errs)
+	a.True(rc.closed)
+}
@@ -0,0 +1,311 @@
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/conneroisu/groq-go/internal/schema"
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+)
+
+// Structured performs a chat completion whose output is constrained to the
+// JSON schema reflected from T and returns the parsed output of the first
+// choice along with the raw response.
+//
+// When the content of the choice cannot be parsed or violates the schema, a
+// *groqerr.ErrStructuredOutput holding the raw content is returned once
+// the repair attempts of the request are exhausted.
+func Structured[T any](
+	ctx context.Context,
+	client *Client,
+	request ChatCompletionRequest,
+) (T, *ChatCompletionResponse, error) {
+	outputs, response, err := StructuredChoices[T](ctx, client, request)
+	if err != nil {
+		var zero T
+		return zero, response, err
+	}
+	return outputs[0], response, nil
+}
+
+// StructuredChoices performs a chat completion whose output is constrained
+// to the JSON schema reflected from T and returns the parsed output of
+// every choice, e.g. when the request sets N above 1, along with the raw
+// response.
+//
+// When the content of a choice cannot be parsed or violates the schema, a
+// *groqerr.ErrStructuredOutput holding the raw content is returned once
+// the repair attempts of the request are exhausted.
+func StructuredChoices[T any](
+	ctx context.Context,
+	client *Client,
+	request ChatCompletionRequest,
+) ([]T, *ChatCompletionResponse, error) {
+	var outputs []T
+	response, err := client.structured(
+		ctx,
+		request,
+		reflect.TypeFor[T](),
+		func(i int) any {
+			if i == 0 {
+				outputs = outputs[:0]
+			}
+			// the element is decoded into before the next one is appended
+			outputs = append(outputs, *new(T))
+			return &outputs[i]
+		},
+	)
+	if err != nil {
+		return nil, response, err
+	}
+	return outputs, response, nil
+}
+
+// StructuredStream performs a streamed chat completion whose output is
+// constrained to the JSON schema reflected from T and returns an iterator
+// over the progressively completed values of T, see Partial.
+func StructuredStream[T any](
+	ctx context.Context,
+	client *Client,
+	request ChatCompletionRequest,
+) (iter.Seq2[T, error], error) {
+	format := client.jsonResponseFormat(reflect.TypeFor[T]())
+	request.ResponseFormat = format
+	stream, err := client.ChatCompletionStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	// the content is validated against the schema sent with the request
+	return partial[T](stream, &format.JSONSchema.Schema), nil
+}
+
+// Partial returns an iterator over the progressively completed values of T
+// parsed from the content of the first choice of the stream, e.g. to
+// render a structured output while it streams.
+//
+// A value is yielded each time a field of T is completed, holding the
+// fields completed so far. Once the stream ends, the whole content is
+// validated against the JSON schema reflected from T and strictly decoded,
+// and the final value is yielded last. A violation of the schema yields a
+// *groqerr.ErrStructuredOutput.
+//
+// Iteration stops after the first error, which is yielded. The stream is
+// closed once the loop exits.
+func Partial[T any](stream *ChatCompletionStream) iter.Seq2[T, error] {
+	format := jsonResponseFormat(reflect.TypeFor[T]())
+	return partial[T](stream, &format.JSONSchema.Schema)
+}
+
+// partial returns an iterator over the progressively completed values of T
+// parsed from the content of the first choice of the stream, validating
+// the whole content against the schema, see Partial.
+func partial[T any](
+	stream *ChatCompletionStream,
+	s *schema.Schema,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer stream.Close()
+		var (
+			zero    T
+			acc     ChatCompletionAccumulator
+			content []byte
+			last    []byte
+		)
+		for chunk, err := range stream.chunks() {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			acc.Add(chunk)
+			n := len(content)
+			for _, choice := range chunk.Choices {
+				if choice.Index == 0 {
+					content = append(content, choice.Delta.Content...)
+				}
+			}
+			if len(content) == n {
+				continue
+			}
+			v := s.ParsePartial(trimFence(content))
+			if v == nil {
+				continue
+			}
+			data, err := json.Marshal(v)
+			if err != nil || bytes.Equal(data, last) {
+				continue
+			}
+			last = data
+			var partial T
+			if json.Unmarshal(data, &partial) != nil {
+				continue
+			}
+			if !yield(partial, nil) {
+				return
+			}
+		}
+		response := acc.Response()
+		if len(response.Choices) == 0 {
+			yield(zero, &groqerr.ErrStructuredOutput{
+				ResponseID: response.ID,
+				Err:        errors.New("response has no choices"),
+			})
+			return
+		}
+		var output T
+		err := parseChoice(response, 0, s, &output)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		yield(output, nil)
+	}
+}
+
+// structured performs a chat completion constrained to the JSON schema of
+// the given type, validating and parsing the content of each choice into
+// the output returned for its index, if any.
+//
+// Replies violating the schema are sent back to the model along with the
+// validation errors up to request.RepairAttempts times.
+func (c *Client) structured(
+	ctx context.Context,
+	request ChatCompletionRequest,
+	t reflect.Type,
+	output func(i int) any,
+) (*ChatCompletionResponse, error) {
+	format := c.jsonResponseFormat(t)
+	request.ResponseFormat = format
+	request.Messages = slices.Clone(request.Messages)
+	for attempt := 0; ; attempt++ {
+		response, err := c.ChatCompletion(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Choices) == 0 {
+			return &response, &groqerr.ErrStructuredOutput{
+				ResponseID: response.ID,
+				Err:        errors.New("response has no choices"),
+			}
+		}
+		for i := range response.Choices {
+			out := output(i)
+			if out == nil {
+				continue
+			}
+			err = parseChoice(response, i, &format.JSONSchema.Schema, out)
+			if err != nil {
+				break
+			}
+		}
+		var outErr *groqerr.ErrStructuredOutput
+		if err == nil || attempt >= request.RepairAttempts ||
+			!errors.As(err, &outErr) {
+			return &response, err
+		}
+		request.Messages = append(
+			request.Messages,
+			ChatCompletionMessage{
+				Role:    RoleAssistant,
+				Content: outErr.Content,
+			},
+			ChatCompletionMessage{
+				Role:    RoleUser,
+				Content: fmt.Sprintf(repairPrompt, outErr.Err),
+			},
+		)
+	}
+}
+
+// repairPrompt asks the model to correct a reply violating the JSON schema
+// of the output.
+const repairPrompt = "The previous reply is not valid against the JSON " +
+	"schema: %v. Reply again with only the corrected JSON."
+
+// trimFence drops the opening line of a markdown code block wrapping
+// streamed content, if any.
+func trimFence(content []byte) []byte {
+	if !bytes.HasPrefix(bytes.TrimSpace(content), []byte("```")) {
+		return content
+	}
+	_, block, _ := bytes.Cut(content, []byte("\n"))
+	return block
+}
+
+// jsonResponseFormat returns the response format constraining the output
+// of a chat completion to the JSON schema of the given type, rewritten to
+// the strict mode subset if the client is configured to.
+func (c *Client) jsonResponseFormat(t reflect.Type) *ChatResponseFormat {
+	format := jsonResponseFormat(t)
+	if c.strictSchemas {
+		format.JSONSchema.Schema = *format.JSONSchema.Schema.Strict()
+	}
+	return format
+}
+
+// jsonResponseFormat returns the response format constraining the output
+// of a chat completion to the JSON schema of the given type.
+func jsonResponseFormat(t reflect.Type) *ChatResponseFormat {
+	s := schema.ReflectInline(t)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := s.Title
+	if name == "" {
+		name = schema.ToSnakeCase(t.Name())
+	}
+	if name == "" {
+		name = "output"
+	}
+	return &ChatResponseFormat{
+		JSONSchema: &JSONSchema{
+			Name:        name,
+			Description: s.Description,
+			Schema:      *s,
+			Strict:      true,
+		},
+		Type: FormatJSON,
+	}
+}
+
+// parseChoice validates the content of a choice of the response against
+// the schema and parses it into output.
+//
+// Content wrapped in a markdown code block is unwrapped first.
+func parseChoice(
+	response ChatCompletionResponse,
+	index int,
+	s *schema.Schema,
+	output any,
+) error {
+	content := response.Choices[index].Message.Content
+	raw := content
+	if _, block, ok := strings.Cut(content, "```"); ok {
+		block, _, _ = strings.Cut(block, "```")
+		// drop the language of the block, e.g. ```json
+		if !strings.HasPrefix(strings.TrimSpace(block), "{") &&
+			!strings.HasPrefix(strings.TrimSpace(block), "[") {
+			_, block, _ = strings.Cut(block, "\n")
+		}
+		raw = block
+	}
+	err := s.Validate([]byte(raw))
+	if err == nil {
+		err = json.Unmarshal([]byte(raw), output)
+	}
+	if err != nil {
+		return &groqerr.ErrStructuredOutput{
+			ResponseID: response.ID,
+			Choice:     index,
+			Content:    content,
+			Err:        err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/stretchr/testify/assert"
+)
+
+type poem struct {
+	Title string `json:"title"`
+	Lines int    `json:"lines"`
+}
+
+// handleStructuredEndpoint answers with a choice of the given contents for
+// each of the n choices requested.
+func handleStructuredEndpoint(
+	t *testing.T,
+	contents ...string,
+) func(http.ResponseWriter, *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ChatCompletionRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ResponseFormat == nil || req.ResponseFormat.JSONSchema == nil {
+			http.Error(w, "missing json schema", http.StatusBadRequest)
+			return
+		}
+		n := max(req.N, 1)
+		choices := make([]ChatCompletionChoice, n)
+		for i := range choices {
+			choices[i] = ChatCompletionChoice{
+				Index: i,
+				Message: ChatCompletionMessage{
+					Role:    RoleAssistant,
+					Content: contents[i%len(contents)],
+				},
+				FinishReason: ReasonStop,
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		err = json.NewEncoder(w).Encode(ChatCompletionResponse{
+			ID:      "chatcmpl-" + req.ResponseFormat.JSONSchema.Name,
+			Choices: choices,
+			Usage:   Usage{TotalTokens: 42},
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestStructured(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		handleStructuredEndpoint(
+			t,
+			`{"title":"Autumn","lines":4}`,
+			"```json\n{\"title\":\"Winter\",\"lines\":2}\n```",
+		),
+	)
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Write a poem."},
+		},
+	}
+	output, response, err := Structured[poem](ctx, client, req)
+	a.NoError(err)
+	a.Equal(poem{Title: "Autumn", Lines: 4}, output)
+	a.Equal("chatcmpl-poem", response.ID)
+	a.Equal(42, response.Usage.TotalTokens)
+
+	req.N = 2
+	outputs, _, err := StructuredChoices[*poem](ctx, client, req)
+	a.NoError(err)
+	a.Equal([]*poem{
+		{Title: "Autumn", Lines: 4},
+		{Title: "Winter", Lines: 2},
+	}, outputs)
+
+	var legacy poem
+	a.NoError(client.ChatCompletionJSON(ctx, req, &legacy))
+	a.Equal("Autumn", legacy.Title)
+}
+
+type profile struct {
+	Name    string `json:"name"`
+	Age     *int   `json:"age"`
+	Address *struct {
+		City string `json:"city"`
+	} `json:"address,omitempty"`
+}
+
+func TestStructuredNullablePointers(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		handleStructuredEndpoint(
+			t,
+			`{"name":"bob","age":null,"address":null}`,
+		),
+	)
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Describe bob."},
+		},
+	}
+	var out profile
+	a.NoError(client.ChatCompletionJSON(context.Background(), req, &out))
+	a.Equal(profile{Name: "bob"}, out)
+	out, _, err := Structured[profile](context.Background(), client, req)
+	a.NoError(err)
+	a.Equal("bob", out.Name)
+	a.Nil(out.Age)
+}
+
+func TestStructuredParseError(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		handleStructuredEndpoint(t, `{"title":"Autumn","lines":`),
+	)
+	_, response, err := Structured[poem](
+		context.Background(),
+		client,
+		ChatCompletionRequest{
+			Model: ModelLlama3370BVersatile,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Write a poem."},
+			},
+		},
+	)
+	var parseErr *groqerr.ErrStructuredOutput
+	a.ErrorAs(err, &parseErr)
+	a.Equal(0, parseErr.Choice)
+	a.Equal(`{"title":"Autumn","lines":`, parseErr.Content)
+	a.Equal("chatcmpl-poem", parseErr.ResponseID)
+	a.NotNil(response)
+}
+
+func TestStructuredRepair(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	var requests []ChatCompletionRequest
+	replies := []string{
+		`{"title":"Autumn","lines":"four"}`,
+		`{"title":"Autumn"}`,
+		`{"title":"Autumn","lines":4}`,
+	}
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			var req ChatCompletionRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			requests = append(requests, req)
+			w.Header().Set("Content-Type", "application/json")
+			err = json.NewEncoder(w).Encode(ChatCompletionResponse{
+				Choices: []ChatCompletionChoice{{
+					Message: ChatCompletionMessage{
+						Role:    RoleAssistant,
+						Content: replies[len(requests)-1],
+					},
+					FinishReason: ReasonStop,
+				}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	req := ChatCompletionRequest{
+		Model: ModelLlama3370BVersatile,
+		Messages: []ChatCompletionMessage{
+			{Role: RoleUser, Content: "Write a poem."},
+		},
+		RepairAttempts: 1,
+	}
+	_, _, err := Structured[poem](context.Background(), client, req)
+	var outErr *groqerr.ErrStructuredOutput
+	a.ErrorAs(err, &outErr)
+	a.Equal(`{"title":"Autumn"}`, outErr.Content)
+	a.ErrorContains(err, `missing required property "lines"`)
+	a.Len(requests, 2)
+	a.Len(requests[1].Messages, 3)
+	a.Equal(replies[0], requests[1].Messages[1].Content)
+	a.Contains(
+		requests[1].Messages[2].Content,
+		"/lines: expected integer, got string",
+	)
+	a.Len(req.Messages, 1)
+
+	requests = nil
+	req.RepairAttempts = 2
+	output, _, err := Structured[poem](context.Background(), client, req)
+	a.NoError(err)
+	a.Equal(poem{Title: "Autumn", Lines: 4}, output)
+	a.Len(requests, 3)
+	a.Len(requests[2].Messages, 5)
+}
+
+// contentChunks returns the chunks of a stream of the given content
+// deltas.
+func contentChunks(deltas ...string) []string {
+	chunks := make([]string, len(deltas))
+	for i, delta := range deltas {
+		content, _ := json.Marshal(delta)
+		chunks[i] = `{"id":"chatcmpl-1","choices":[{"index":0,` +
+			`"delta":{"content":` + string(content) + `}}]}`
+	}
+	return chunks
+}
+
+func TestPartial(t *testing.T) {
+	a := assert.New(t)
+	stream, rc := newTestStream(streamBody(contentChunks(
+		`{"title":"Aut`,
+		`umn","li`,
+		`nes":4`,
+		`}`,
+	)...))
+	var outputs []poem
+	for output, err := range Partial[poem](stream) {
+		a.NoError(err)
+		outputs = append(outputs, output)
+	}
+	a.Equal([]poem{
+		{},
+		{Title: "Autumn"},
+		{Title: "Autumn", Lines: 4},
+		{Title: "Autumn", Lines: 4},
+	}, outputs)
+	a.True(rc.closed)
+}
+
+func TestPartialInvalid(t *testing.T) {
+	a := assert.New(t)
+	stream, _ := newTestStream(streamBody(contentChunks(
+		`{"title":"Autumn",`,
+		`"lines":"four"}`,
+	)...))
+	var (
+		outputs []poem
+		errs    []error
+	)
+	for output, err := range Partial[poem](stream) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		outputs = append(outputs, output)
+	}
+	a.Equal([]poem{{Title: "Autumn"}}, outputs)
+	a.Len(errs, 1)
+	var outErr *groqerr.ErrStructuredOutput
+	a.ErrorAs(errs[0], &outErr)
+	a.Equal("chatcmpl-1", outErr.ResponseID)
+}
+
+func TestStructuredStrictSchemas(t *testing.T) {
+	a := assert.New(t)
+	client, server, teardown := setupGroqTestServer()
+	defer teardown()
+	WithStrictSchemas()(client)
+	var format *JSONSchema
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, r *http.Request) {
+			var req ChatCompletionRequest
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			format = req.ResponseFormat.JSONSchema
+			w.Header().Set("Content-Type", "application/json")
+			err = json.NewEncoder(w).Encode(ChatCompletionResponse{
+				Choices: []ChatCompletionChoice{{
+					Message: ChatCompletionMessage{
+						Role:    RoleAssistant,
+						Content: `{"title":"Autumn","note":""}`,
+					},
+					FinishReason: ReasonStop,
+				}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+	type draft struct {
+		Title string `json:"title"`
+		Note  string `json:"note,omitempty"`
+	}
+	output, _, err := Structured[draft](
+		context.Background(),
+		client,
+		ChatCompletionRequest{
+			Model: ModelLlama3370BVersatile,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Draft a poem."},
+			},
+		},
+	)
+	a.NoError(err)
+	a.Equal(draft{Title: "Autumn"}, output)
+	a.Equal([]string{"title", "note"}, format.Schema.Required)
+	a.Empty(format.Schema.Lint())
+
+	// streams are validated against the strict schema they are sent
+	server.RegisterHandler(
+		"/v1/chat/completions",
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, err := w.Write([]byte(streamBody(contentChunks(
+				`{"title":"Autumn"}`,
+			)...)))
+			a.NoError(err)
+		},
+	)
+	outputs, err := StructuredStream[draft](
+		context.Background(),
+		client,
+		ChatCompletionRequest{
+			Model: ModelLlama3370BVersatile,
+			Messages: []ChatCompletionMessage{
+				{Role: RoleUser, Content: "Draft a poem."},
+			},
+		},
+	)
+	a.NoError(err)
+	var outErr *groqerr.ErrStructuredOutput
+	for _, err := range outputs {
+		if err != nil {
+			a.ErrorAs(err, &outErr)
+		}
+	}
+	a.NotNil(outErr, "the note required by the strict schema is missing")
+}
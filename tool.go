@@ -0,0 +1,84 @@
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/conneroisu/groq-go/pkg/schema"
+	"github.com/conneroisu/groq-go/pkg/tools"
+)
+
+// NewAgentTool creates an agent tool from a typed go function.
+//
+// The parameters of the tool are reflected from the Args struct, honouring
+// its json and jsonschema tags. The arguments of each call are decoded
+// into Args, and the result of the function is marshaled to JSON to form
+// the content of the tool message. String results are used as is.
+func NewAgentTool[Args, Result any](
+	name, description string,
+	fn func(ctx context.Context, args Args) (Result, error),
+) (AgentTool, error) {
+	params, err := reflectParameters(reflect.TypeFor[Args]())
+	if err != nil {
+		return AgentTool{}, fmt.Errorf(
+			"reflecting parameters of tool %s: %w",
+			name,
+			err,
+		)
+	}
+	return AgentTool{
+		Tool: tools.Tool{
+			Type: tools.ToolTypeFunction,
+			Function: tools.FunctionDefinition{
+				Name:        name,
+				Description: description,
+				Parameters:  params,
+			},
+		},
+		Func: func(ctx context.Context, call tools.ToolCall) (string, error) {
+			var args Args
+			if call.Function.Arguments != "" {
+				err := json.Unmarshal([]byte(call.Function.Arguments), &args)
+				if err != nil {
+					return "", fmt.Errorf(
+						"decoding arguments of tool %s: %w",
+						name,
+						err,
+					)
+				}
+			}
+			result, err := fn(ctx, args)
+			if err != nil {
+				return "", err
+			}
+			if s, ok := any(result).(string); ok {
+				return s, nil
+			}
+			content, err := json.Marshal(result)
+			if err != nil {
+				return "", fmt.Errorf(
+					"encoding result of tool %s: %w",
+					name,
+					err,
+				)
+			}
+			return string(content), nil
+		},
+	}, nil
+}
+
+// reflectParameters reflects the function parameters of a tool from a
+// struct type.
+func reflectParameters(t reflect.Type) (tools.FunctionParameters, error) {
+	var params tools.FunctionParameters
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return params, fmt.Errorf("arguments must be a struct, got %s", t)
+	}
+	r := &schema.Reflector{Anonymous: true, DoNotReference: true}
+	return schema.ToFunctionParameters(r.ReflectFromType(t))
+}
@@ -0,0 +1,113 @@
+package groq
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conneroisu/groq-go/pkg/tools"
+	"github.com/stretchr/testify/assert"
+)
+
+type weatherArgs struct {
+	City  string `json:"city" jsonschema:"description=The city to get the weather of"`
+	Units string `json:"units,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+	Days  []int  `json:"days,omitempty" jsonschema:"maxItems=7"`
+}
+
+type tripArgs struct {
+	Destination struct {
+		City    string `json:"city"`
+		Country string `json:"country" jsonschema:"enum=FR,enum=DE"`
+	} `json:"destination"`
+	Stops []struct {
+		City   string `json:"city"`
+		Nights int    `json:"nights" jsonschema:"minimum=1"`
+	} `json:"stops"`
+}
+
+type weatherResult struct {
+	Temperature float64 `json:"temperature"`
+}
+
+func TestNewAgentTool(t *testing.T) {
+	a := assert.New(t)
+	ctx := context.Background()
+	tool, err := NewAgentTool(
+		"get_weather",
+		"Gets the weather of a city.",
+		func(_ context.Context, args weatherArgs) (weatherResult, error) {
+			if args.City == "" {
+				return weatherResult{}, errors.New("no city")
+			}
+			return weatherResult{Temperature: 21.5}, nil
+		},
+	)
+	a.NoError(err)
+	a.Equal(tools.ToolTypeFunction, tool.Tool.Type)
+	a.Equal("get_weather", tool.Tool.Function.Name)
+	params := tool.Tool.Function.Parameters
+	a.Equal("object", params.Type)
+	a.Equal([]string{"city"}, params.Required)
+	a.Equal(tools.PropertyDefinition{
+		Type:        "string",
+		Description: "The city to get the weather of",
+	}, params.Properties["city"])
+	a.Equal([]any{"celsius", "fahrenheit"}, params.Properties["units"].Enum)
+	a.Equal("integer", params.Properties["days"].Items.Type)
+	a.Equal(7, *params.Properties["days"].MaxItems)
+
+	content, err := tool.Func(ctx, tools.ToolCall{
+		Function: tools.FunctionCall{
+			Name:      "get_weather",
+			Arguments: `{"city":"Paris"}`,
+		},
+	})
+	a.NoError(err)
+	a.JSONEq(`{"temperature":21.5}`, content)
+
+	_, err = tool.Func(ctx, tools.ToolCall{})
+	a.EqualError(err, "no city")
+	_, err = tool.Func(ctx, tools.ToolCall{
+		Function: tools.FunctionCall{Arguments: `{"city":`},
+	})
+	a.ErrorContains(err, "decoding arguments of tool get_weather")
+
+	echo, err := NewAgentTool(
+		"echo",
+		"Echoes its input.",
+		func(_ context.Context, args struct{ Text string }) (string, error) {
+			return args.Text, nil
+		},
+	)
+	a.NoError(err)
+	content, err = echo.Func(ctx, tools.ToolCall{
+		Function: tools.FunctionCall{Arguments: `{"Text":"hi"}`},
+	})
+	a.NoError(err)
+	a.Equal("hi", content)
+
+	trip, err := NewAgentTool(
+		"plan_trip",
+		"Plans a trip.",
+		func(_ context.Context, _ tripArgs) (string, error) { return "", nil },
+	)
+	a.NoError(err)
+	params = trip.Tool.Function.Parameters
+	destination := params.Properties["destination"]
+	a.Equal("object", destination.Type)
+	a.Equal([]string{"city", "country"}, destination.Required)
+	a.Equal([]any{"FR", "DE"}, destination.Properties["country"].Enum)
+	stops := params.Properties["stops"]
+	a.Equal("array", stops.Type)
+	a.Equal("object", stops.Items.Type)
+	a.Equal("integer", stops.Items.Properties["nights"].Type)
+	a.Equal(1.0, *stops.Items.Properties["nights"].Minimum)
+
+	_, err = NewAgentTool(
+		"bad",
+		"Takes a string.",
+		func(_ context.Context, _ string) (string, error) { return "", nil },
+	)
+	a.Error(err)
+}
@@ -1,17 +1,20 @@
 package groq
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/conneroisu/groq-go/internal/schema"
 	"github.com/conneroisu/groq-go/internal/streams"
 	"github.com/conneroisu/groq-go/pkg/builders"
 	"github.com/conneroisu/groq-go/pkg/groqerr"
+	"github.com/conneroisu/groq-go/pkg/schema"
 	"github.com/conneroisu/groq-go/pkg/tools"
 )
 
@@ -104,8 +107,26 @@ type (
 		StreamOptions *StreamOptions `json:"stream_options,omitempty"`
 		// Disable the default behavior of parallel tool calls by setting it: false.
 		ParallelToolCalls any `json:"parallel_tool_calls,omitempty"`
-		// RetryDelay is the delay between retries.
+		// RetryDelay overrides the initial backoff of the client's
+		// retry policy for this request.
+		//
+		// Use WithRetryPolicy to configure retries for all requests.
 		RetryDelay time.Duration `json:"-"`
+		// StreamIdleTimeout aborts a streamed chat completion with a
+		// groqerr.ErrStreamIdleTimeout when no bytes arrive for the given
+		// interval.
+		//
+		// Unlike the deadline of the request context, it bounds the time
+		// between chunks rather than the whole stream. It is disabled when
+		// zero.
+		StreamIdleTimeout time.Duration `json:"-"`
+		// RepairAttempts is the number of corrective attempts made by
+		// ChatCompletionJSON, Structured and StructuredChoices when a
+		// reply does not validate against the JSON schema of the output.
+		//
+		// Each attempt sends the invalid reply back to the model along
+		// with the validation errors. It is disabled when zero.
+		RepairAttempts int `json:"-"`
 	}
 	// ChatCompletionResponse represents a response structure for chat
 	// completion API.
@@ -321,6 +342,19 @@ type (
 		// chunk which contains the token usage statistics for the
 		// entire request.
 		Usage *Usage `json:"usage,omitempty"`
+		// XGroq holds the groq specific fields of the chat completion
+		// stream response.
+		XGroq *XGroq `json:"x_groq,omitempty"`
+	}
+	// XGroq represents the groq specific fields of a chat completion
+	// stream response.
+	XGroq struct {
+		// ID is the groq request id.
+		ID string `json:"id,omitempty"`
+		// Usage is the token usage statistics for the entire request.
+		//
+		// It is only present on the last chunk of the stream.
+		Usage *Usage `json:"usage,omitempty"`
 	}
 	// PromptAnnotation represents the prompt annotation.
 	PromptAnnotation struct {
@@ -461,6 +495,8 @@ type (
 	endpoint       string
 	fullURLOptions struct{ model string }
 	fullURLOption  func(*fullURLOptions)
+	sendOptions    struct{ retryDelay time.Duration }
+	sendOption     func(*sendOptions)
 	response       interface{ SetHeader(http.Header) }
 )
 
@@ -494,6 +530,23 @@ type (
 	//
 	// string
 	Moderation string
+	// ModerationRequest is the request of a moderation call.
+	ModerationRequest struct {
+		// Messages are the messages to moderate.
+		Messages []ChatCompletionMessage `json:"messages"`
+		// Model is the moderation model.
+		Model ModerationModel `json:"model,omitempty"`
+	}
+	// ModerationResponse is the response of a moderation call.
+	ModerationResponse struct {
+		// Categories are the categories of harmful content found in the
+		// messages, empty when they are safe.
+		Categories []Moderation `json:"categories"`
+		// Usage is the usage of the moderation.
+		Usage Usage `json:"usage"`
+
+		header http.Header `json:"-"`
+	}
 )
 
 const (
@@ -673,6 +726,11 @@ type (
 // SetHeader sets the header of the response.
 func (r *AudioResponse) SetHeader(header http.Header) { r.header = header }
 
+// SetHeader sets the header of the response.
+func (r *ModerationResponse) SetHeader(header http.Header) {
+	r.header = header
+}
+
 // SetHeader sets the header of the audio text response.
 func (r *audioTextResponse) SetHeader(header http.Header) { r.header = header }
 
@@ -753,3 +811,166 @@ func audioMultipartForm(request AudioRequest, b builders.FormBuilder) error {
 	}
 	return b.Close()
 }
+
+// # [Embeddings](https://console.groq.com/docs/api-reference#embeddings-create)
+
+type (
+	// EmbeddingRequest represents a request structure for the embeddings
+	// API.
+	EmbeddingRequest struct {
+		// Model is the model to use for the embeddings.
+		Model EmbeddingModel `json:"model"`
+		// Input is the batch of texts to embed.
+		Input []string `json:"input"`
+		// EncodingFormat is the format the embeddings are returned in by
+		// the API.
+		//
+		// Embeddings are always decoded into float32 values.
+		EncodingFormat EmbeddingEncodingFormat `json:"encoding_format,omitempty"`
+		// User is the user of the embeddings request.
+		User string `json:"user,omitempty"`
+	}
+	// EmbeddingResponse represents a response structure for the embeddings
+	// API.
+	EmbeddingResponse struct {
+		// Object is the object of the response.
+		Object string `json:"object"`
+		// Data is the embeddings of the response, one per input.
+		Data []Embedding `json:"data"`
+		// Model is the model of the response.
+		Model EmbeddingModel `json:"model"`
+		// Usage is the usage of the response.
+		Usage Usage `json:"usage"`
+
+		header http.Header `json:"-"`
+	}
+	// Embedding is the embedding of a single input.
+	Embedding struct {
+		// Object is the object of the embedding.
+		Object string `json:"object"`
+		// Index is the index of the input the embedding belongs to.
+		Index int `json:"index"`
+		// Embedding is the embedding vector.
+		Embedding []float32 `json:"embedding"`
+	}
+	// EmbeddingEncodingFormat is the encoding format of embeddings.
+	//
+	// string
+	EmbeddingEncodingFormat string
+)
+
+const (
+	// EmbeddingEncodingFormatFloat is the float embedding encoding format.
+	EmbeddingEncodingFormatFloat EmbeddingEncodingFormat = "float"
+	// EmbeddingEncodingFormatBase64 is the base64 embedding encoding
+	// format.
+	//
+	// It reduces the size of the response body.
+	EmbeddingEncodingFormatBase64 EmbeddingEncodingFormat = "base64"
+)
+
+// SetHeader sets the header of the embedding response.
+func (r *EmbeddingResponse) SetHeader(header http.Header) { r.header = header }
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// It decodes embeddings returned either as an array of floats or as base64
+// encoded little-endian float32 values.
+func (e *Embedding) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Object    string          `json:"object"`
+		Index     int             `json:"index"`
+		Embedding json.RawMessage `json:"embedding"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	e.Object = raw.Object
+	e.Index = raw.Index
+	e.Embedding = nil
+	if len(raw.Embedding) == 0 || raw.Embedding[0] != '"' {
+		return json.Unmarshal(raw.Embedding, &e.Embedding)
+	}
+	var encoded string
+	err = json.Unmarshal(raw.Embedding, &encoded)
+	if err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("decoding base64 embedding: %w", err)
+	}
+	if len(decoded)%4 != 0 {
+		return fmt.Errorf(
+			"decoding base64 embedding: invalid length %d",
+			len(decoded),
+		)
+	}
+	e.Embedding = make([]float32, len(decoded)/4)
+	for i := range e.Embedding {
+		e.Embedding[i] = math.Float32frombits(
+			binary.LittleEndian.Uint32(decoded[i*4:]),
+		)
+	}
+	return nil
+}
+
+// # [Models](https://console.groq.com/docs/api-reference#models)
+
+type (
+	// ModelList represents the response of the models API listing all
+	// models available to the api key.
+	ModelList struct {
+		// Object is the object of the response.
+		Object string `json:"object"`
+		// Data is the models of the response.
+		Data []ModelMetadata `json:"data"`
+
+		header http.Header `json:"-"`
+	}
+	// ModelMetadata represents the metadata of a model returned by the
+	// models API.
+	ModelMetadata struct {
+		// ID is the id of the model.
+		ID Model `json:"id"`
+		// Object is the object of the model.
+		Object string `json:"object"`
+		// Created is the unix time, in seconds, at which the model was
+		// created.
+		Created int64 `json:"created"`
+		// OwnedBy is the organization that owns the model.
+		OwnedBy string `json:"owned_by"`
+		// Active is whether the model is currently served.
+		Active bool `json:"active"`
+		// ContextWindow is the maximum number of tokens of the context
+		// of the model.
+		ContextWindow int `json:"context_window"`
+		// MaxCompletionTokens is the maximum number of tokens the model
+		// can generate, when reported by the API.
+		MaxCompletionTokens int `json:"max_completion_tokens,omitempty"`
+
+		header http.Header `json:"-"`
+	}
+)
+
+// SetHeader sets the header of the model list.
+func (r *ModelList) SetHeader(header http.Header) { r.header = header }
+
+// SetHeader sets the header of the model metadata.
+func (r *ModelMetadata) SetHeader(header http.Header) { r.header = header }
+
+// CreatedAt returns the time at which the model was created.
+func (r ModelMetadata) CreatedAt() time.Time {
+	return time.Unix(r.Created, 0)
+}
+
+// Get returns the metadata of the model with the given id in the list.
+func (r ModelList) Get(id Model) (ModelMetadata, bool) {
+	for _, m := range r.Data {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return ModelMetadata{}, false
+}